@@ -0,0 +1,131 @@
+// 比较构造整个文档和按需访问的开销，数据与 json 包的 benchmark 相同，
+// 是 agl 在 go、webkit、chromium 项目中的修改记录。
+
+package gjson
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+)
+
+var codeJSON []byte
+
+func codeInit() {
+	f, err := os.Open("json/testdata/code.json.gz")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		panic(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		panic(err)
+	}
+	codeJSON = data
+}
+
+// 每种方式都取出 tree.kids[0].name 和 tree.kids[0].kids[0].name
+const (
+	codeKidName      = "go"
+	codeGrandkidName = "src"
+)
+
+func BenchmarkCodeUnmarshalAny(b *testing.B) {
+	b.ReportAllocs()
+	if codeJSON == nil {
+		b.StopTimer()
+		codeInit()
+		b.StartTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		var v any
+		if err := Unmarshal(codeJSON, &v); err != nil {
+			b.Fatal("Unmarshal:", err)
+		}
+		kid := ValueOf(v).Get("tree").Get("kids").Index(0)
+		if kid.Get("name").String() != codeKidName || kid.Get("kids").Index(0).Get("name").String() != codeGrandkidName {
+			b.Fatal("unexpected value")
+		}
+	}
+	b.SetBytes(int64(len(codeJSON)))
+}
+
+func BenchmarkCodeQuery(b *testing.B) {
+	b.ReportAllocs()
+	if codeJSON == nil {
+		b.StopTimer()
+		codeInit()
+		b.StartTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		name, err := Query(codeJSON, "tree.kids.0.name")
+		if err != nil {
+			b.Fatal("Query:", err)
+		}
+		grandkid, err := Query(codeJSON, "tree.kids.0.kids.0.name")
+		if err != nil {
+			b.Fatal("Query:", err)
+		}
+		if name.String() != codeKidName || grandkid.String() != codeGrandkidName {
+			b.Fatal("unexpected value")
+		}
+	}
+	b.SetBytes(int64(len(codeJSON)))
+}
+
+func BenchmarkCodeLazy(b *testing.B) {
+	b.ReportAllocs()
+	if codeJSON == nil {
+		b.StopTimer()
+		codeInit()
+		b.StartTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		l, err := ParseLazy(codeJSON)
+		if err != nil {
+			b.Fatal("ParseLazy:", err)
+		}
+		kid := l.Get("tree").Get("kids").Index(0)
+		if !kid.Get("name").equal(codeKidName) || !kid.Get("kids").Index(0).Get("name").equal(codeGrandkidName) {
+			b.Fatal("unexpected value")
+		}
+	}
+	b.SetBytes(int64(len(codeJSON)))
+}
+
+// 小文档上的访问，ParseLazy 不分配内存
+var smallJSON = []byte(`{"id": 12345, "user": {"name": "alice", "email": "alice@example.com", "admin": false},
+	"tags": ["a", "b", "c"], "score": 98.5, "created": "2022-05-01T10:00:00Z"}`)
+
+func BenchmarkSmallUnmarshalAny(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v any
+		if err := Unmarshal(smallJSON, &v); err != nil {
+			b.Fatal("Unmarshal:", err)
+		}
+		if ValueOf(v).Get("user").Get("admin").Bool() || ValueOf(v).Get("id").Int() != 12345 {
+			b.Fatal("unexpected value")
+		}
+	}
+	b.SetBytes(int64(len(smallJSON)))
+}
+
+func BenchmarkSmallLazy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l, err := ParseLazy(smallJSON)
+		if err != nil {
+			b.Fatal("ParseLazy:", err)
+		}
+		if l.Get("user").Get("admin").Bool() || l.Get("id").Int() != 12345 {
+			b.Fatal("unexpected value")
+		}
+	}
+	b.SetBytes(int64(len(smallJSON)))
+}
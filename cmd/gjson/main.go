@@ -0,0 +1,262 @@
+// gjson 是在 shell 中使用 JSON 和 markdown 工具的命令行程序。
+//
+// 用法：
+//
+//	gjson validate [file...]                检查每个文件是否是合法的 JSON
+//	gjson fmt [-indent s] [-w] [file...]    格式化 JSON
+//	gjson compact [-w] [file...]            去掉 JSON 中的空白
+//	gjson query path [file...]              按照 path 取出一个值，path 的语法见 gjson.Query
+//	gjson toc [-min n] [-max n] [-insert] [-w] [file...]
+//	                                        生成 markdown 文档的目录，-insert 把目录写到
+//	                                        <!-- toc --> 和 <!-- tocstop --> 之间并输出整个文档
+//	gjson outline [file...]                 以 JSON 输出 markdown 文档的标题树，格式见 gjson.Outline
+//	gjson lint [file...]                    检查 markdown 文档的标题结构，规则见 gjson.LintMarkdown，
+//	                                        每个问题输出为 "file:line: message (rule)"
+//
+// 没有 file 或者 file 为 "-" 时读取标准输入。-w 把结果写回文件而不是标准输出。
+// 出错时在标准错误输出 "file:line:column: message"，退出码为 1；命令行参数错误时退出码为 2。
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"gjson"
+	"gjson/json"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+const usage = `usage: gjson <command> [flags] [file...]
+
+commands:
+  validate   check that each input is valid JSON
+  fmt        indent JSON
+  compact    remove insignificant space from JSON
+  query      print the value selected by a path
+  toc        generate a markdown table of contents
+  outline    print the heading tree of a markdown document as JSON
+  lint       check the heading structure of a markdown document
+`
+
+// command 处理一个输入，name 是出错时显示的文件名，返回的结果写到标准输出或者写回文件
+type command func(name string, data []byte) ([]byte, error)
+
+// run 执行命令行 args，返回退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	flags := flag.NewFlagSet("gjson "+args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	write := false
+	var cmd command
+	switch args[0] {
+	case "validate":
+		cmd = validate
+	case "fmt":
+		indent := flags.String("indent", "  ", "indentation of each level")
+		flags.BoolVar(&write, "w", false, "write the result back to the files")
+		cmd = func(name string, data []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, data, "", *indent); err != nil {
+				return nil, positioned(name, data, err)
+			}
+			buf.WriteByte('\n')
+			return buf.Bytes(), nil
+		}
+	case "compact":
+		flags.BoolVar(&write, "w", false, "write the result back to the files")
+		cmd = func(name string, data []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, data); err != nil {
+				return nil, positioned(name, data, err)
+			}
+			buf.WriteByte('\n')
+			return buf.Bytes(), nil
+		}
+	case "query":
+		cmd = func(name string, data []byte) ([]byte, error) {
+			return query(name, data, flags.Arg(0))
+		}
+	case "toc":
+		minLevel := flags.Int("min", 1, "lowest heading level in the table of contents")
+		maxLevel := flags.Int("max", 6, "highest heading level in the table of contents")
+		insert := flags.Bool("insert", false, "insert the table of contents between the <!-- toc --> and <!-- tocstop --> markers")
+		flags.BoolVar(&write, "w", false, "write the result back to the files, implies -insert")
+		cmd = func(name string, data []byte) ([]byte, error) {
+			var result string
+			var err error
+			if *insert || write {
+				result, err = gjson.InsertTOC(string(data), *minLevel, *maxLevel)
+			} else {
+				result, err = gjson.MarkdownTOC(bytes.NewReader(data), *minLevel, *maxLevel)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", name, err)
+			}
+			return []byte(result), nil
+		}
+	case "outline":
+		cmd = func(name string, data []byte) ([]byte, error) {
+			outline, err := gjson.MarkdownOutline(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", name, err)
+			}
+			result, err := json.MarshalIndent(outline, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", name, err)
+			}
+			return append(result, '\n'), nil
+		}
+	case "lint":
+		cmd = lint
+	default:
+		fmt.Fprintf(stderr, "gjson: unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	files := flags.Args()
+	if args[0] == "query" {
+		if len(files) == 0 {
+			fmt.Fprintln(stderr, "usage: gjson query path [file...]")
+			return 2
+		}
+		files = files[1:]
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if write {
+		for _, file := range files {
+			if file == "-" {
+				fmt.Fprintln(stderr, "gjson: -w cannot be used with standard input")
+				return 2
+			}
+		}
+	}
+
+	status := 0
+	for _, file := range files {
+		if err := runFile(cmd, file, write, stdin, stdout); err != nil {
+			fmt.Fprintln(stderr, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// runFile 对一个文件执行 cmd，file 为 "-" 时读取 stdin
+func runFile(cmd command, file string, write bool, stdin io.Reader, stdout io.Writer) error {
+	name, data, err := file, []byte(nil), error(nil)
+	if file == "-" {
+		name = "<stdin>"
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	result, err := cmd(name, data)
+	if err != nil {
+		return err
+	}
+	if write {
+		if bytes.Equal(result, data) {
+			return nil
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(file, result, info.Mode().Perm())
+	}
+	_, err = stdout.Write(result)
+	return err
+}
+
+func validate(name string, data []byte) ([]byte, error) {
+	if json.Valid(data) {
+		return nil, nil
+	}
+	// Valid 只返回是否合法，用 Compact 得到出错的位置和原因
+	return nil, positioned(name, data, json.Compact(new(bytes.Buffer), data))
+}
+
+func lint(name string, data []byte) ([]byte, error) {
+	diagnostics, err := gjson.LintMarkdown(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if len(diagnostics) == 0 {
+		return nil, nil
+	}
+	lines := make([]string, len(diagnostics))
+	for i, d := range diagnostics {
+		lines[i] = fmt.Sprintf("%s:%d: %s (%s)", name, d.Line, d.Message, d.Rule)
+	}
+	return nil, errors.New(strings.Join(lines, "\n"))
+}
+
+func query(name string, data []byte, path string) ([]byte, error) {
+	// Query 不检查选中的值之后的内容，先检查整个输入，不合法的输入总是报错
+	if _, err := validate(name, data); err != nil {
+		return nil, err
+	}
+	v, err := gjson.Query(data, path)
+	if err != nil {
+		var e *gjson.SyntaxError
+		if errors.As(err, &e) {
+			return nil, fmt.Errorf("%s:%d:%d: %v", name, e.Line, e.Column, err)
+		}
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if !v.Exists() {
+		return nil, fmt.Errorf("%s: no value at path %q", name, path)
+	}
+	result, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return append(result, '\n'), nil
+}
+
+// positioned 在 json 包的语法错误前面加上文件名、行号和列号。
+// json.SyntaxError 的 Offset 是读到出错的字符为止的字节数，行号和列号指向这个字符；
+// 输入提前结束时 Offset 是输入的长度，行号和列号指向最后一个字符之后
+func positioned(name string, data []byte, err error) error {
+	var e *json.SyntaxError
+	if !errors.As(err, &e) {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	offset := int(e.Offset)
+	if offset != len(data) || !strings.HasPrefix(e.Error(), "unexpected end of JSON input") {
+		offset--
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if data[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return fmt.Errorf("%s:%d:%d: %v", name, line, utf8.RuneCount(data[lineStart:offset])+1, err)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args   []string
+		stdin  string
+		stdout string
+		stderr string
+		status int
+	}{
+		{[]string{"validate"}, `{"a": [1, 2]}`, "", "", 0},
+		{[]string{"validate"}, "{\n  \"a\": [1,\n  2 x]\n}", "", "<stdin>:3:5: invalid character 'x' after array element\n", 1},
+		{[]string{"validate", "-"}, `{"a":`, "", "<stdin>:1:6: unexpected end of JSON input\n", 1},
+		{[]string{"validate"}, "[1,\n", "", "<stdin>:2:1: unexpected end of JSON input\n", 1},
+		{[]string{"validate"}, "", "", "<stdin>:1:1: unexpected end of JSON input\n", 1},
+		{[]string{"fmt"}, `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}\n", "", 0},
+		{[]string{"fmt", "-indent", "\t"}, `{"a":{}}`, "{\n\t\"a\": {}\n}\n", "", 0},
+		{[]string{"compact"}, "{ \"a\" : [ 1 , 2 ] }\n", "{\"a\":[1,2]}\n", "", 0},
+		{[]string{"compact"}, "[1,\n2,]", "", "<stdin>:2:3: invalid character ']' looking for beginning of value\n", 1},
+		{[]string{"query", "a.#.b"}, `{"a": [{"b": 1}, {"b": "x"}]}`, "[1,\"x\"]\n", "", 0},
+		{[]string{"query", "a.c"}, `{"a": {}}`, "", "<stdin>: no value at path \"a.c\"\n", 1},
+		{[]string{"query", "a.c"}, "{\"a\":\n {]}", "", "<stdin>:2:3: invalid character ']' looking for beginning of object key string\n", 1},
+		{[]string{"query", "0"}, "[1, nope", "", "<stdin>:1:6: invalid character 'o' in literal null (expecting 'u')\n", 1},
+		{[]string{"query", "a"}, `{"a":1} trailing`, "", "<stdin>:1:9: invalid character 't' after top-level value\n", 1},
+		{[]string{"toc", "-min", "2"}, "# T\n## A\n### B\n", "- [A](#a)\n  - [B](#b)\n", "", 0},
+		{[]string{"toc", "-insert"}, "<!-- toc -->\n<!-- tocstop -->\n# T\n", "<!-- toc -->\n- [T](#t)\n<!-- tocstop -->\n# T\n", "", 0},
+		{[]string{"toc", "-insert"}, "# T\n", "", "<stdin>: missing <!-- toc --> marker\n", 1},
+		{[]string{"toc", "-min", "3", "-max", "2"}, "# T\n", "", "<stdin>: invalid toc level range 3-2\n", 1},
+		{[]string{"outline"}, "# T\n## A\n", "[\n  {\n    \"title\": \"T\",\n    \"number\": \"1\",\n    \"level\": 1,\n    \"anchor\": \"t\",\n    \"line\": 1,\n    \"children\": [\n      {\n        \"title\": \"A\",\n        \"number\": \"1.1\",\n        \"level\": 2,\n        \"anchor\": \"a\",\n        \"line\": 2\n      }\n    ]\n  }\n]\n", "", 0},
+		{[]string{"outline"}, "no headings\n", "[]\n", "", 0},
+		{[]string{"lint"}, "# T\n## A\n", "", "", 0},
+		{[]string{"lint"}, "# T\n### A\n#\n", "", "<stdin>:2: heading level 3 follows level 1 (skipped-level)\n<stdin>:3: another top-level heading, the title is at line 1 (multiple-titles)\n<stdin>:3: empty heading (empty)\n", 1},
+		{[]string{"fmt", "-w"}, "{}", "", "gjson: -w cannot be used with standard input\n", 2},
+		{[]string{"query"}, "{}", "", "usage: gjson query path [file...]\n", 2},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+		if status != tt.status || stdout.String() != tt.stdout || stderr.String() != tt.stderr {
+			t.Errorf("run(%q) = %d, stdout %q, stderr %q\nexpected %d, stdout %q, stderr %q",
+				tt.args, status, stdout.String(), stderr.String(), tt.status, tt.stdout, tt.stderr)
+		}
+	}
+
+	for _, args := range [][]string{nil, {"nope"}, {"fmt", "-nope"}} {
+		var stdout, stderr bytes.Buffer
+		if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 2 || stderr.Len() == 0 {
+			t.Errorf("run(%q) = %d, stderr %q, expected usage error", args, status, stderr.String())
+		}
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	doc := filepath.Join(dir, "README.md")
+	os.WriteFile(good, []byte(`{"a": 1}`), 0o644)
+	os.WriteFile(bad, []byte("[\n1 2]"), 0o644)
+	os.WriteFile(doc, []byte("# T\n<!-- toc -->\nold\n<!-- tocstop -->\n## A\n"), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"validate", good, bad, filepath.Join(dir, "missing.json")}, nil, &stdout, &stderr); status != 1 {
+		t.Fatalf("validate = %d", status)
+	}
+	lines := strings.Split(strings.TrimSuffix(stderr.String(), "\n"), "\n")
+	if len(lines) != 2 || lines[0] != bad+":2:3: invalid character '2' after array element" || !strings.HasPrefix(lines[1], "open ") {
+		t.Fatalf("validate stderr %q", stderr.String())
+	}
+
+	stderr.Reset()
+	if status := run([]string{"compact", "-w", good}, nil, &stdout, &stderr); status != 0 || stdout.Len() != 0 {
+		t.Fatalf("compact -w = %d, stdout %q, stderr %q", status, stdout.String(), stderr.String())
+	}
+	if data, _ := os.ReadFile(good); string(data) != "{\"a\":1}\n" {
+		t.Fatalf("compact -w wrote %q", data)
+	}
+
+	if status := run([]string{"toc", "-w", "-min", "2", doc}, nil, &stdout, &stderr); status != 0 {
+		t.Fatalf("toc -w = %d, stderr %q", status, stderr.String())
+	}
+	if data, _ := os.ReadFile(doc); string(data) != "# T\n<!-- toc -->\n- [A](#a)\n<!-- tocstop -->\n## A\n" {
+		t.Fatalf("toc -w wrote %q", data)
+	}
+}
@@ -0,0 +1,771 @@
+package gjson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gjson/json"
+)
+
+// unmarshal 把 t.data 中的 JSON 值直接写入 v 指向的 Go 值，
+// 规则与 json.Unmarshal 保持一致：struct、slice、array、map、指针以及基本类型，
+// 支持 json struct tag，也支持 json.Unmarshaler 和 encoding.TextUnmarshaler。
+func (t *parser) unmarshal(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	t.passBlank()
+	// 和 json 包一样，从 rv 而不是 rv.Elem() 开始，这样顶层的 Unmarshaler 也能生效
+	t.value(rv)
+	t.passBlank()
+	if !t.end() {
+		t.fail("end of input")
+	}
+	return t.savedError
+}
+
+// saveError 只记录第一个类型错误，解析会继续进行，和 json 包的 decodeState.saveError 一致
+func (t *parser) saveError(err error) {
+	if t.savedError == nil {
+		t.savedError = t.addErrorContext(err)
+	}
+}
+
+// addErrorContext 给 UnmarshalTypeError 补上出错字段所在的 struct 和字段路径
+func (t *parser) addErrorContext(err error) error {
+	if t.errorStruct != nil || len(t.fieldStack) > 0 {
+		switch err := err.(type) {
+		case *json.UnmarshalTypeError:
+			err.Struct = t.errorStruct.Name()
+			err.Field = strings.Join(t.fieldStack, ".")
+		}
+	}
+	return err
+}
+
+func (t *parser) typeError(what string, typ reflect.Type) {
+	t.saveError(&json.UnmarshalTypeError{Value: what, Type: typ, Offset: int64(t.index)})
+}
+
+// skip 跳过当前的 JSON 值，返回这个值在 t.data 中对应的原始字节
+func (t *parser) skip() []byte {
+	start := t.index
+	t.parseValue()
+	return t.data[start:t.index]
+}
+
+// value 解析当前位置的 JSON 值并写入 v，v 无效时只跳过这个值
+func (t *parser) value(v reflect.Value) {
+	if !v.IsValid() {
+		t.skip()
+		return
+	}
+	switch t.curChar() {
+	case '[':
+		t.array(v)
+	case '{':
+		t.object(v)
+	default:
+		t.literal(v)
+	}
+}
+
+// indirect 沿着指针向下走，必要时分配新的值，直到遇到非指针。
+// 遇到 Unmarshaler 时提前返回，逻辑与 json 包的 indirect 相同。
+func indirect(v reflect.Value, decodingNull bool) (json.Unmarshaler, encoding.TextUnmarshaler, reflect.Value) {
+	v0 := v
+	haveAddr := false
+
+	// 具名类型先取地址，这样指针接收者的方法也能被找到
+	if v.Kind() != reflect.Pointer && v.Type().Name() != "" && v.CanAddr() {
+		haveAddr = true
+		v = v.Addr()
+	}
+	for {
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Pointer && !e.IsNil() && (!decodingNull || e.Elem().Kind() == reflect.Pointer) {
+				haveAddr = false
+				v = e
+				continue
+			}
+		}
+
+		if v.Kind() != reflect.Pointer {
+			break
+		}
+
+		if decodingNull && v.CanSet() {
+			break
+		}
+
+		// var v any; v = &v 这种自己指向自己的情况，避免死循环
+		if v.Elem().Kind() == reflect.Interface && v.Elem().Elem() == v {
+			v = v.Elem()
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 && v.CanInterface() {
+			if u, ok := v.Interface().(json.Unmarshaler); ok {
+				return u, nil, reflect.Value{}
+			}
+			if !decodingNull {
+				if u, ok := v.Interface().(encoding.TextUnmarshaler); ok {
+					return nil, u, reflect.Value{}
+				}
+			}
+		}
+
+		if haveAddr {
+			v = v0
+			haveAddr = false
+		} else {
+			v = v.Elem()
+		}
+	}
+	return nil, nil, v
+}
+
+func (t *parser) array(v reflect.Value) {
+	u, ut, pv := indirect(v, false)
+	if u != nil {
+		if err := u.UnmarshalJSON(t.skip()); err != nil {
+			t.saveError(err)
+		}
+		return
+	}
+	if ut != nil {
+		t.typeError("array", v.Type())
+		t.skip()
+		return
+	}
+	v = pv
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			item, _ := t.tryArray()
+			v.Set(reflect.ValueOf(item))
+			return
+		}
+		fallthrough
+	default:
+		t.typeError("array", v.Type())
+		t.skip()
+		return
+	case reflect.Array, reflect.Slice:
+	}
+
+	t.enter()
+	defer t.leave()
+	// 跳过 '['
+	t.next()
+	t.passBlank()
+	i := 0
+	if t.curChar() == ']' {
+		t.next()
+	} else {
+		for {
+			if v.Kind() == reflect.Slice {
+				if i >= v.Cap() {
+					newcap := v.Cap() + v.Cap()/2
+					if newcap < 4 {
+						newcap = 4
+					}
+					newv := reflect.MakeSlice(v.Type(), v.Len(), newcap)
+					reflect.Copy(newv, v)
+					v.Set(newv)
+				}
+				if i >= v.Len() {
+					v.SetLen(i + 1)
+				}
+			}
+
+			if i < v.Len() {
+				t.value(v.Index(i))
+			} else {
+				// 定长数组已经放满了，多余的元素直接跳过
+				t.value(reflect.Value{})
+			}
+			i++
+
+			if t.passComma(']') {
+				break
+			}
+		}
+	}
+
+	if i < v.Len() {
+		if v.Kind() == reflect.Array {
+			z := reflect.Zero(v.Type().Elem())
+			for ; i < v.Len(); i++ {
+				v.Index(i).Set(z)
+			}
+		} else {
+			v.SetLen(i)
+		}
+	}
+	if i == 0 && v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func (t *parser) object(v reflect.Value) {
+	u, ut, pv := indirect(v, false)
+	if u != nil {
+		if err := u.UnmarshalJSON(t.skip()); err != nil {
+			t.saveError(err)
+		}
+		return
+	}
+	if ut != nil {
+		t.typeError("object", v.Type())
+		t.skip()
+		return
+	}
+	v = pv
+	typ := v.Type()
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		item, _ := t.tryObject()
+		v.Set(reflect.ValueOf(item))
+		return
+	}
+
+	var fields []field
+	switch v.Kind() {
+	case reflect.Map:
+		switch typ.Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !reflect.PointerTo(typ.Key()).Implements(textUnmarshalerType) {
+				t.typeError("object", typ)
+				t.skip()
+				return
+			}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(typ))
+		}
+	case reflect.Struct:
+		fields = cachedTypeFields(typ)
+	default:
+		t.typeError("object", typ)
+		t.skip()
+		return
+	}
+
+	var mapElem reflect.Value
+	origStruct, origDepth := t.errorStruct, len(t.fieldStack)
+	seen := t.newObjectKeys()
+
+	t.enter()
+	defer t.leave()
+	// 跳过 '{'
+	t.next()
+	t.passBlank()
+	if t.curChar() == '}' {
+		t.next()
+		return
+	}
+	for {
+		offset := t.index
+		key := t.parseObjectKey()
+
+		var subv reflect.Value
+		quoted := false
+		// 大小写不敏感地匹配到同一个字段的 key 也算作重复
+		dupKey := key
+		if v.Kind() == reflect.Map {
+			elemType := typ.Elem()
+			if !mapElem.IsValid() {
+				mapElem = reflect.New(elemType).Elem()
+			} else {
+				mapElem.Set(reflect.Zero(elemType))
+			}
+			subv = mapElem
+		} else if f := lookupField(fields, key, t.caseSensitive); f != nil {
+			subv = v
+			quoted = f.quoted
+			for _, i := range f.index {
+				if subv.Kind() == reflect.Pointer {
+					if subv.IsNil() {
+						// 嵌入了未导出类型的指针时无法分配新值
+						if !subv.CanSet() {
+							t.saveError(fmt.Errorf("gjson: cannot set embedded pointer to unexported struct: %v", subv.Type().Elem()))
+							subv = reflect.Value{}
+							quoted = false
+							break
+						}
+						subv.Set(reflect.New(subv.Type().Elem()))
+					}
+					subv = subv.Elem()
+				}
+				subv = subv.Field(i)
+			}
+			t.fieldStack = append(t.fieldStack, f.name)
+			t.errorStruct = typ
+			dupKey = f.name
+		}
+
+		// 按照重复 key 的策略决定是否写入这个值，collect 表示要和之前的值收集到一起
+		var collect *seenKey
+		var collectOld any
+		if seen != nil {
+			k, dup := seen[dupKey]
+			switch {
+			case !dup:
+				seen[dupKey] = &seenKey{}
+			case t.duplicateKeys == json.CollectDuplicateKeys && subv.IsValid() &&
+				subv.Kind() == reflect.Interface && subv.NumMethod() == 0:
+				collect = k
+				if v.Kind() == reflect.Struct {
+					collectOld = subv.Interface()
+				}
+			default:
+				if t.duplicateKeys != json.FirstKeyWins {
+					t.saveError(&json.DuplicateKeyError{Key: key, Offset: int64(offset)})
+				}
+				subv = reflect.Value{}
+				quoted = false
+			}
+		}
+
+		t.expect(':', "':' after object key")
+		t.passBlank()
+
+		if quoted {
+			t.quotedValue(subv)
+		} else {
+			t.value(subv)
+		}
+
+		if collect != nil && v.Kind() == reflect.Struct {
+			value, _ := t.duplicate(collect, collectOld, key, subv.Interface(), offset)
+			subv.Set(reflect.ValueOf(value))
+		}
+
+		if v.Kind() == reflect.Map && subv.IsValid() {
+			if kv, ok := t.mapKey(typ.Key(), key); ok {
+				if collect != nil {
+					value, _ := t.duplicate(collect, v.MapIndex(kv).Interface(), key, subv.Interface(), offset)
+					subv.Set(reflect.ValueOf(value))
+				}
+				v.SetMapIndex(kv, subv)
+			}
+		}
+
+		t.fieldStack = t.fieldStack[:origDepth]
+		t.errorStruct = origStruct
+		if t.passComma('}') {
+			break
+		}
+	}
+}
+
+// mapKey 把 object 的 key 转换为 map 的 key 类型
+func (t *parser) mapKey(kt reflect.Type, key string) (reflect.Value, bool) {
+	switch {
+	case reflect.PointerTo(kt).Implements(textUnmarshalerType):
+		kv := reflect.New(kt)
+		if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			t.saveError(err)
+			return reflect.Value{}, false
+		}
+		return kv.Elem(), true
+	case kt.Kind() == reflect.String:
+		return reflect.ValueOf(key).Convert(kt), true
+	}
+	switch kt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || reflect.Zero(kt).OverflowInt(n) {
+			t.typeError("number "+key, kt)
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(n).Convert(kt), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || reflect.Zero(kt).OverflowUint(n) {
+			t.typeError("number "+key, kt)
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(n).Convert(kt), true
+	}
+	panic("gjson: unexpected map key type")
+}
+
+// quotedValue 处理带 ",string" 选项的字段：值本身是一个字符串，字符串的内容才是真正的字面量
+func (t *parser) quotedValue(v reflect.Value) {
+	switch t.valueChar() {
+	case 'n':
+		t.literal(v)
+	case '"':
+		item, _ := t.tryString()
+		if err := quotedLiteral([]byte(item.(string)), v); err != nil {
+			t.saveError(err)
+		}
+	default:
+		t.saveError(fmt.Errorf("gjson: invalid use of ,string struct tag, trying to unmarshal unquoted value into %v", v.Type()))
+		t.skip()
+	}
+}
+
+// quotedLiteral 把 ",string" 字段中字符串的内容当作 null、bool、number 或 string 解析并写入 v
+func quotedLiteral(item []byte, v reflect.Value) (err error) {
+	inner := newParser(item)
+	invalid := fmt.Errorf("gjson: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type())
+	defer func() {
+		// 字符串内容的语法错误统一报告为 ",string" 的用法错误
+		if r := recover(); r != nil {
+			if _, ok := r.(*SyntaxError); !ok {
+				panic(r)
+			}
+			err = invalid
+		}
+	}()
+
+	switch inner.curChar() {
+	case '[', '{', eof:
+		return invalid
+	}
+	inner.literal(v)
+	if !inner.end() {
+		return invalid
+	}
+	return inner.savedError
+}
+
+var numberType = reflect.TypeOf(json.Number(""))
+
+// literal 解析 null、bool、string、number 并写入 v
+func (t *parser) literal(v reflect.Value) {
+	c := t.valueChar()
+	isNull := c == 'n'
+	u, ut, pv := indirect(v, isNull)
+	if u != nil {
+		if err := u.UnmarshalJSON(t.skip()); err != nil {
+			t.saveError(err)
+		}
+		return
+	}
+	if ut != nil {
+		if c != '"' {
+			what := "number"
+			switch c {
+			case 'n':
+				what = "null"
+			case 't', 'f':
+				what = "bool"
+			}
+			t.typeError(what, v.Type())
+			t.skip()
+			return
+		}
+		item, _ := t.tryString()
+		if err := ut.UnmarshalText([]byte(item.(string))); err != nil {
+			t.saveError(err)
+		}
+		return
+	}
+	v = pv
+
+	switch c {
+	case 'n':
+		t.tryNull()
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+			// 基本类型遇到 null 时保持原值
+		}
+	case 't', 'f':
+		item, _ := t.tryBool()
+		switch v.Kind() {
+		default:
+			t.typeError("bool", v.Type())
+		case reflect.Bool:
+			v.SetBool(item.(bool))
+		case reflect.Interface:
+			if v.NumMethod() == 0 {
+				v.Set(reflect.ValueOf(item))
+			} else {
+				t.typeError("bool", v.Type())
+			}
+		}
+	case '"':
+		item, _ := t.tryString()
+		s := item.(string)
+		switch v.Kind() {
+		default:
+			t.typeError("string", v.Type())
+		case reflect.Slice:
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				t.typeError("string", v.Type())
+				break
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				t.saveError(err)
+				break
+			}
+			v.SetBytes(b)
+		case reflect.String:
+			if v.Type() == numberType && !isValidNumber(s) {
+				t.saveError(fmt.Errorf("gjson: invalid number literal, trying to unmarshal %q into Number", s))
+				break
+			}
+			v.SetString(s)
+		case reflect.Interface:
+			if v.NumMethod() == 0 {
+				v.Set(reflect.ValueOf(s))
+			} else {
+				t.typeError("string", v.Type())
+			}
+		}
+	default:
+		s, ok := t.scanNum()
+		if !ok {
+			t.fail("value")
+		}
+		switch v.Kind() {
+		default:
+			if v.Kind() == reflect.String && v.Type() == numberType {
+				v.SetString(s)
+				break
+			}
+			t.typeError("number", v.Type())
+		case reflect.Interface:
+			if v.NumMethod() != 0 {
+				t.typeError("number", v.Type())
+				break
+			}
+			v.Set(reflect.ValueOf(t.convertNumber(s)))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || v.OverflowInt(n) {
+				t.typeError("number "+s, v.Type())
+				break
+			}
+			v.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n, err := strconv.ParseUint(s, 10, 64)
+			if err != nil || v.OverflowUint(n) {
+				t.typeError("number "+s, v.Type())
+				break
+			}
+			v.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(s, v.Type().Bits())
+			if err != nil || v.OverflowFloat(n) {
+				t.typeError("number "+s, v.Type())
+				break
+			}
+			v.SetFloat(n)
+		}
+	}
+}
+
+// isValidNumber 判断 s 是否是合法的 JSON 数字
+func isValidNumber(s string) (ok bool) {
+	t := newParser([]byte(s))
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isSyntax := r.(*SyntaxError); !isSyntax {
+				panic(r)
+			}
+			ok = false
+		}
+	}()
+	_, ok = t.scanNum()
+	return ok && t.end()
+}
+
+// field 描述 struct 中一个可以被 JSON 赋值的字段
+type field struct {
+	name   string
+	tag    bool
+	index  []int
+	typ    reflect.Type
+	quoted bool
+}
+
+// lookupField 先做精确匹配，找不到再退回到大小写不敏感的匹配，和 json 包一致。
+// caseSensitive 为 true 时只做精确匹配
+func lookupField(fields []field, key string, caseSensitive bool) *field {
+	for i := range fields {
+		if fields[i].name == key {
+			return &fields[i]
+		}
+	}
+	if caseSensitive {
+		return nil
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, key) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+func cachedTypeFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields 按广度优先遍历 struct 以及匿名嵌入的 struct，收集所有字段。
+// 同名字段按 json 包的规则取舍：层级浅的优先，同一层里带 tag 的优先，仍然无法区分就都丢弃。
+func typeFields(t reflect.Type) []field {
+	current := []field{}
+	next := []field{{typ: t}}
+	visited := map[reflect.Type]bool{}
+	var fields []field
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					quoted := false
+					if hasOption(opts, "string") {
+						switch ft.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64,
+							reflect.String:
+							quoted = true
+						}
+					}
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					fields = append(fields, field{
+						name:   name,
+						tag:    tagged,
+						index:  index,
+						typ:    ft,
+						quoted: quoted,
+					})
+					continue
+				}
+
+				// 没有名字的匿名 struct，下一层继续展开
+				next = append(next, field{name: ft.Name(), index: index, typ: ft})
+			}
+		}
+	}
+
+	// 按名字分组，再按层级、tag 排序，每组只保留占优的字段
+	sort.SliceStable(fields, func(i, j int) bool {
+		x := fields
+		if x[i].name != x[j].name {
+			return x[i].name < x[j].name
+		}
+		if len(x[i].index) != len(x[j].index) {
+			return len(x[i].index) < len(x[j].index)
+		}
+		return x[i].tag && !x[j].tag
+	})
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fi.name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		if dominant, ok := dominantField(fields[i : i+advance]); ok {
+			out = append(out, dominant)
+		}
+	}
+
+	// 恢复字段的声明顺序
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].index, out[j].index
+		for k := range a {
+			if k >= len(b) {
+				return false
+			}
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return out
+}
+
+// dominantField 在同名的一组字段中选出占优的那个，fields 已经按层级和 tag 排好序
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tag == fields[1].tag {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
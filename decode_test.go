@@ -0,0 +1,167 @@
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gjson/json"
+)
+
+type decodeInner struct {
+	Url    string
+	Height int
+	Width  int
+}
+
+type decodeEmbedded struct {
+	Tag string `json:"tag"`
+}
+
+type decodeImage struct {
+	decodeEmbedded
+	Width     int
+	Height    int
+	Title     string `json:"title"`
+	Thumbnail *decodeInner
+	Animated  bool
+	IDs       []uint32
+	Ratio     float32
+	Count     int64  `json:",string"`
+	Ignored   string `json:"-"`
+	Extra     map[string]any
+	Created   time.Time
+	Raw       json.RawMessage
+	Number    json.Number
+}
+
+func TestUnmarshalTyped(t *testing.T) {
+	data := `{
+		"tag": "photo",
+		"Width": 800,
+		"height": 600,
+		"title": "View from 15th Floor",
+		"Thumbnail": {"Url": "http://www.example.com/image/481989943", "Height": 125, "Width": 100},
+		"Animated": true,
+		"IDs": [116, 943, 234, 38793],
+		"Ratio": 1.5,
+		"Count": "9007199254740993",
+		"Ignored": "nope",
+		"Extra": {"a": [1, "b", null]},
+		"Created": "2022-05-01T10:00:00Z",
+		"Raw": {"keep": [1, 2]},
+		"Number": 12345678901234567890
+	}`
+	expected := decodeImage{
+		decodeEmbedded: decodeEmbedded{Tag: "photo"},
+		Width:          800,
+		Height:         600,
+		Title:          "View from 15th Floor",
+		Thumbnail:      &decodeInner{Url: "http://www.example.com/image/481989943", Height: 125, Width: 100},
+		Animated:       true,
+		IDs:            []uint32{116, 943, 234, 38793},
+		Ratio:          1.5,
+		Count:          9007199254740993,
+		Extra:          map[string]any{"a": []any{float64(1), "b", nil}},
+		Created:        time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC),
+		Raw:            json.RawMessage(`{"keep": [1, 2]}`),
+		Number:         json.Number("12345678901234567890"),
+	}
+
+	var result decodeImage
+	if err := Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unmarshal result %+v, expected: %+v", result, expected)
+	}
+
+	var ints map[int]string
+	if err := Unmarshal([]byte(`{"1": "a", "-2": "b"}`), &ints); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(ints, map[int]string{1: "a", -2: "b"}) {
+		t.Fatalf("Unmarshal result %v", ints)
+	}
+
+	var arr [2]string
+	if err := Unmarshal([]byte(`["a", "b", "c"]`), &arr); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if arr != [2]string{"a", "b"} {
+		t.Fatalf("Unmarshal result %v", arr)
+	}
+
+	var b []byte
+	if err := Unmarshal([]byte(`"aGVsbG8="`), &b); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if string(b) != "hello" {
+		t.Fatalf("Unmarshal result %q", b)
+	}
+
+	p := new(int)
+	if err := Unmarshal([]byte(`null`), &p); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p != nil {
+		t.Fatalf("Unmarshal null into pointer: %v", p)
+	}
+}
+
+func TestUnmarshalTypedErrors(t *testing.T) {
+	var s struct {
+		A int
+		B string
+	}
+	err := Unmarshal([]byte(`{"A": "x", "B": "ok"}`), &s)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("expected UnmarshalTypeError, got %v", err)
+	}
+	if typeErr.Field != "A" || typeErr.Value != "string" {
+		t.Fatalf("unexpected error %+v", typeErr)
+	}
+	// 类型错误不会中断解析，后面的字段仍然被赋值
+	if s.B != "ok" {
+		t.Fatalf("expected B to be decoded, got %q", s.B)
+	}
+
+	var small int8
+	if err := Unmarshal([]byte(`300`), &small); err == nil || !strings.Contains(err.Error(), "number 300") {
+		t.Fatalf("expected overflow error, got %v", err)
+	}
+
+	var notPtr struct{}
+	var invalid *json.InvalidUnmarshalError
+	if err := Unmarshal([]byte(`{}`), notPtr); !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidUnmarshalError, got %v", err)
+	}
+}
+
+func TestUnmarshalCaseSensitive(t *testing.T) {
+	type T struct {
+		ID   int
+		Name string `json:"name"`
+	}
+	data := []byte(`{"id": 1, "ID": 2, "NAME": "x", "name": "y"}`)
+	var s T
+	if err := Unmarshal(data, &s); err != nil || s != (T{ID: 2, Name: "y"}) {
+		t.Fatalf("Unmarshal = %+v, %v", s, err)
+	}
+	s = T{}
+	if err := Unmarshal([]byte(`{"id": 1, "NAME": "x"}`), &s); err != nil || s != (T{ID: 1, Name: "x"}) {
+		t.Fatalf("Unmarshal = %+v, %v", s, err)
+	}
+	s = T{}
+	if err := Unmarshal([]byte(`{"id": 1, "NAME": "x"}`), &s, CaseSensitive()); err != nil || s != (T{}) {
+		t.Fatalf("Unmarshal with CaseSensitive = %+v, %v", s, err)
+	}
+	// 只有精确匹配的 key 才算作同一个字段，"id" 不再是 "ID" 的重复
+	s = T{}
+	if err := Unmarshal(data, &s, CaseSensitive(), DuplicateKeys(json.RejectDuplicateKeys)); err != nil || s != (T{ID: 2, Name: "y"}) {
+		t.Fatalf("Unmarshal with CaseSensitive = %+v, %v", s, err)
+	}
+}
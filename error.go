@@ -0,0 +1,73 @@
+package gjson
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"gjson/json"
+)
+
+// eof 是 parser 读到输入结尾时 curChar 返回的值
+const eof rune = -1
+
+// SyntaxError 描述 JSON 语法错误，包含出错的位置、字符以及期望的内容
+type SyntaxError struct {
+	Offset   int64  // 出错字符的字节偏移，从 0 开始
+	Line     int    // 出错字符所在的行，从 1 开始
+	Column   int    // 出错字符所在的列，从 1 开始，按 UTF-8 字符计数
+	Char     rune   // 出错的字符，读到输入结尾时为 -1
+	Expected string // 期望出现的内容，例如 "',' or ']'"
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Char == eof {
+		return fmt.Sprintf("gjson: unexpected end of input at line %d, column %d: expecting %s", e.Line, e.Column, e.Expected)
+	}
+	return fmt.Sprintf("gjson: invalid character %q at line %d, column %d: expecting %s", e.Char, e.Line, e.Column, e.Expected)
+}
+
+// newSyntaxError 根据 offset 计算出行号和列号
+func newSyntaxError(data []byte, offset int, expected string) *SyntaxError {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	e := &SyntaxError{Offset: int64(offset), Line: 1, Char: eof, Expected: expected}
+	lineStart := 0
+	for i := 0; i < offset; i++ {
+		if data[i] == '\n' {
+			e.Line++
+			lineStart = i + 1
+		}
+	}
+	e.Column = utf8.RuneCount(data[lineStart:offset]) + 1
+	if offset < len(data) {
+		e.Char, _ = utf8.DecodeRune(data[offset:])
+	}
+	return e
+}
+
+// fail 在当前位置抛出语法错误，由 recover 转换为返回值
+func (t *parser) fail(expected string) {
+	t.failAt(t.index, expected)
+}
+
+func (t *parser) failAt(offset int, expected string) {
+	panic(newSyntaxError(t.data, offset, expected))
+}
+
+// recover 把 fail 抛出的 *SyntaxError、limitError 抛出的 *json.LimitError
+// 以及 Handler 返回的错误写入 err，其他 panic 继续向上抛出
+func (t *parser) recover(err *error) {
+	if r := recover(); r != nil {
+		switch r := r.(type) {
+		case *SyntaxError:
+			*err = r
+		case *json.LimitError:
+			*err = r
+		case handlerError:
+			*err = r.err
+		default:
+			panic(r)
+		}
+	}
+}
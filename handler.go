@@ -0,0 +1,57 @@
+package gjson
+
+// Handler 接收 Parse 产生的解析事件，用来在不构造 map[string]any/[]any 的情况下处理 JSON。
+// object 的事件顺序是 StartObject、(Key、值的事件)*、EndObject，array 是 StartArray、(值的事件)*、EndArray。
+// 任何一个方法返回错误都会中断解析，Parse 原样返回这个错误。
+type Handler interface {
+	StartObject() error
+	Key(key string) error
+	EndObject() error
+	StartArray() error
+	EndArray() error
+	String(s string) error
+	// Number 收到数字字面量的原始文本，由 Handler 决定转换成什么类型
+	Number(n Number) error
+	Bool(b bool) error
+	Null() error
+}
+
+// Parse 解析 data 并把每个值依次交给 h，不会构造解析结果。
+// data 不是合法的 JSON 时返回 *SyntaxError，此前已经产生的事件不会撤回。
+// opts 中的 UseNumber、OrderedObjects、DuplicateKeys、Tolerant 对 Parse 没有作用。
+func Parse(data []byte, h Handler, opts ...Option) (err error) {
+	p := newParser(data, opts...)
+	p.handler = h
+	p.numberMode = NumberString
+	p.tolerant = false
+	defer p.recover(&err)
+	p.checkSize()
+	p.parse()
+	return nil
+}
+
+// handlerError 包装 Handler 返回的错误，由 recover 还原
+type handlerError struct {
+	err error
+}
+
+// emit 在 Handler 返回错误时中断解析
+func (t *parser) emit(err error) {
+	if err != nil {
+		panic(handlerError{err})
+	}
+}
+
+// emitPrimitive 把 tryPrimitive 的结果交给 Handler，数字在 Parse 中总是 Number
+func (t *parser) emitPrimitive(item any) {
+	switch item := item.(type) {
+	case nil:
+		t.emit(t.handler.Null())
+	case bool:
+		t.emit(t.handler.Bool(item))
+	case string:
+		t.emit(t.handler.String(item))
+	case Number:
+		t.emit(t.handler.Number(item))
+	}
+}
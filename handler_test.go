@@ -0,0 +1,75 @@
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// recordHandler 把收到的事件记录为字符串，stopAt 不为空时在收到这个 key 后返回 errStop
+type recordHandler struct {
+	events []string
+	stopAt string
+}
+
+var errStop = errors.New("stop")
+
+func (h *recordHandler) add(event string) error {
+	h.events = append(h.events, event)
+	return nil
+}
+
+func (h *recordHandler) StartObject() error { return h.add("{") }
+func (h *recordHandler) EndObject() error   { return h.add("}") }
+func (h *recordHandler) StartArray() error  { return h.add("[") }
+func (h *recordHandler) EndArray() error    { return h.add("]") }
+func (h *recordHandler) String(s string) error {
+	return h.add(strconv.Quote(s))
+}
+func (h *recordHandler) Number(n Number) error { return h.add("n:" + string(n)) }
+func (h *recordHandler) Bool(b bool) error     { return h.add(strconv.FormatBool(b)) }
+func (h *recordHandler) Null() error           { return h.add("null") }
+func (h *recordHandler) Key(key string) error {
+	h.add("key:" + key)
+	if key == h.stopAt {
+		return errStop
+	}
+	return nil
+}
+
+func TestParseHandler(t *testing.T) {
+	data := `{"a": [1, -2.5e3, {}], "b": {"c": "x\ty", "d": [true, false, null, []]}}`
+	expected := []string{
+		"{", "key:a", "[", "n:1", "n:-2.5e3", "{", "}", "]",
+		"key:b", "{", "key:c", `"x\ty"`, "key:d", "[", "true", "false", "null", "[", "]", "]", "}",
+		"}",
+	}
+	h := &recordHandler{}
+	if err := Parse([]byte(data), h, UseNumber(NumberFloat64), OrderedObjects()); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(h.events, expected) {
+		t.Fatalf("Parse events %q, expected %q", h.events, expected)
+	}
+
+	// Handler 返回的错误中断解析并原样返回
+	h = &recordHandler{stopAt: "c"}
+	if err := Parse([]byte(data), h); err != errStop {
+		t.Fatalf("Parse error %v, expected %v", err, errStop)
+	}
+	if last := h.events[len(h.events)-1]; last != "key:c" {
+		t.Fatalf("Parse continued after error, last event %q", last)
+	}
+
+	// 语法错误之前的事件已经交给 Handler
+	h = &recordHandler{}
+	err := Parse([]byte(`[1, 2,]`), h)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Offset != 6 {
+		t.Fatalf("Parse error %v, expected syntax error at offset 6", err)
+	}
+	if !reflect.DeepEqual(h.events, []string{"[", "n:1", "n:2"}) {
+		t.Fatalf("Parse events before error %q", h.events)
+	}
+}
@@ -0,0 +1,193 @@
+package gjson
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// mdHeading 是 markdown 文档中的一个标题
+type mdHeading struct {
+	// 1 到 6，setext 标题的 "===" 是 1，"---" 是 2
+	level int
+	// 去掉标记和首尾空白之后的完整文本，可能为空
+	text string
+	// 标题所在的行号，从 1 开始；setext 标题是文本的第一行
+	line int
+}
+
+// parseHeadings 读取整个 markdown 文档，按照 CommonMark 的规则找出所有的标题：
+//   - ATX 标题：最多 3 个空格的缩进，1 到 6 个 '#'，之后是空格或者行尾，结尾的 '#' 序列会被去掉
+//   - setext 标题：段落下面一行只有 '=' 或者 '-'，段落的多行文本用空格连接
+//   - fenced code block（``` 或 ~~~）和缩进代码块中的内容不是标题
+//
+// 列表、引用等其他块中的标题不会被识别，行内的 markdown 语法保留原样
+func parseHeadings(r io.Reader) ([]mdHeading, error) {
+	var (
+		headings []mdHeading
+		// 正在读取的段落的各行以及它开始的行号，遇到 setext 的下划线时成为标题的文本
+		para     []string
+		paraLine int
+		// 当前 fenced code block 的开始标记，不在代码块中时为空
+		fence string
+	)
+	br := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line == "" && err == io.EOF {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		indent, rest := splitIndent(line)
+
+		switch {
+		case fence != "":
+			if indent < 4 && isClosingFence(rest, fence) {
+				fence = ""
+			}
+		case strings.TrimSpace(line) == "":
+			para = nil
+		case indent >= 4:
+			// 缩进代码块不能打断段落，这时是段落的延续
+			if para != nil {
+				para = append(para, strings.TrimSpace(rest))
+			}
+		case openingFence(rest) != "":
+			fence, para = openingFence(rest), nil
+		default:
+			if level, text, ok := parseATX(rest); ok {
+				headings = append(headings, mdHeading{level: level, text: text, line: lineNo})
+				para = nil
+			} else if level := setextLevel(rest); level > 0 && para != nil {
+				headings = append(headings, mdHeading{level: level, text: strings.Join(para, " "), line: paraLine})
+				para = nil
+			} else if isThematicBreak(rest) || isBlockStart(rest) {
+				para = nil
+			} else {
+				if para == nil {
+					paraLine = lineNo
+				}
+				para = append(para, strings.TrimSpace(rest))
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return headings, nil
+}
+
+// splitIndent 返回 line 开头空白的宽度（tab 对齐到 4 的倍数）以及去掉空白之后的内容
+func splitIndent(line string) (int, string) {
+	width := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width, line[i:]
+		}
+	}
+	return width, ""
+}
+
+// parseATX 解析去掉缩进之后的一行 ATX 标题，例如 "## Hello World ##" -> 2, "Hello World"
+func parseATX(rest string) (level int, text string, ok bool) {
+	for level < len(rest) && rest[level] == '#' {
+		level++
+	}
+	if level < 1 || level > 6 {
+		return 0, "", false
+	}
+	if level < len(rest) && rest[level] != ' ' && rest[level] != '\t' {
+		// "#5" 和 "#hashtag" 不是标题
+		return 0, "", false
+	}
+	text = strings.Trim(rest[level:], " \t")
+	// 结尾的 '#' 序列前面必须是空白，"# C#" 的 '#' 属于文本
+	if t := strings.TrimRight(text, "#"); t == "" || t[len(t)-1] == ' ' || t[len(t)-1] == '\t' {
+		text = strings.TrimRight(t, " \t")
+	}
+	return level, text, true
+}
+
+// setextLevel 判断一行是否是 setext 标题的下划线，"===" 返回 1，"---" 返回 2，不是时返回 0
+func setextLevel(rest string) int {
+	rest = strings.TrimRight(rest, " \t")
+	if rest == "" {
+		return 0
+	}
+	switch c := rest[0]; {
+	case strings.Trim(rest, "=") == "":
+		return 1
+	case c == '-' && strings.Trim(rest, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+// isThematicBreak 判断一行是否是分隔线，例如 "***"、"- - -"、"___"
+func isThematicBreak(rest string) bool {
+	if rest == "" || !strings.ContainsRune("-*_", rune(rest[0])) {
+		return false
+	}
+	n := 0
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case rest[0]:
+			n++
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
+// isBlockStart 判断一行是否是引用或者列表项的开头，它们会结束当前的段落
+func isBlockStart(rest string) bool {
+	switch rest[0] {
+	case '>':
+		return true
+	case '-', '*', '+':
+		return len(rest) == 1 || rest[1] == ' ' || rest[1] == '\t'
+	}
+	i := 0
+	for i < len(rest) && i < 9 && '0' <= rest[i] && rest[i] <= '9' {
+		i++
+	}
+	if i == 0 || i == len(rest) || rest[i] != '.' && rest[i] != ')' {
+		return false
+	}
+	return i+1 == len(rest) || rest[i+1] == ' ' || rest[i+1] == '\t'
+}
+
+// openingFence 返回 fenced code block 的开始标记，例如 "```go" 返回 "```"，不是时返回空字符串
+func openingFence(rest string) string {
+	if rest == "" || rest[0] != '`' && rest[0] != '~' {
+		return ""
+	}
+	n := 0
+	for n < len(rest) && rest[n] == rest[0] {
+		n++
+	}
+	// 反引号 fence 的 info string 中不能有反引号
+	if n < 3 || rest[0] == '`' && strings.ContainsRune(rest[n:], '`') {
+		return ""
+	}
+	return rest[:n]
+}
+
+// isClosingFence 判断一行是否结束了以 fence 开始的代码块：相同的字符，长度不小于 fence，之后只有空白
+func isClosingFence(rest, fence string) bool {
+	n := 0
+	for n < len(rest) && rest[n] == fence[0] {
+		n++
+	}
+	return n >= len(fence) && strings.TrimSpace(rest[n:]) == ""
+}
@@ -42,9 +42,10 @@ import (
 //
 // To unmarshal JSON into a struct, Unmarshal matches incoming object
 // keys to the keys used by Marshal (either the struct field name or its tag),
-// preferring an exact match but also accepting a case-insensitive match. By
-// default, object keys which don't have a corresponding struct field are
-// ignored (see Decoder.DisallowUnknownFields for an alternative).
+// preferring an exact match but also accepting a case-insensitive match
+// (see Decoder.CaseSensitive and the CaseSensitive option to require an
+// exact match). By default, object keys which don't have a corresponding
+// struct field are ignored (see Decoder.DisallowUnknownFields for an alternative).
 //
 // To unmarshal JSON into an interface value,
 // Unmarshal stores one of these in the interface value:
@@ -150,6 +151,41 @@ func (e *UnmarshalFieldError) Error() string {
 	return "json: cannot unmarshal object key " + strconv.Quote(e.Key) + " into unexported field " + e.Field.Name + " of type " + e.Type.String()
 }
 
+// A DuplicateKeyPolicy controls what the decoder does when a JSON object
+// contains the same key more than once.
+type DuplicateKeyPolicy int
+
+const (
+	// LastKeyWins stores the value of the last occurrence of a key.
+	// This is the default behavior.
+	LastKeyWins DuplicateKeyPolicy = iota
+
+	// FirstKeyWins keeps the value of the first occurrence of a key
+	// and discards the values of later occurrences.
+	FirstKeyWins
+
+	// RejectDuplicateKeys reports a DuplicateKeyError for the second
+	// occurrence of a key. The first value is kept.
+	RejectDuplicateKeys
+
+	// CollectDuplicateKeys gathers the values of all occurrences of a key,
+	// in input order, into a []interface{}. A key that appears only once is
+	// decoded as usual. Values can only be collected into an empty interface;
+	// for any other destination a duplicate key is reported as a DuplicateKeyError.
+	CollectDuplicateKeys
+)
+
+// A DuplicateKeyError describes a JSON object key that appeared more than
+// once and was rejected by the DuplicateKeyPolicy in effect.
+type DuplicateKeyError struct {
+	Key    string // the repeated key
+	Offset int64  // error occurred after reading Offset bytes
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return "json: duplicate object key " + strconv.Quote(e.Key)
+}
+
 // An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
 // (The argument to Unmarshal must be a non-nil pointer.)
 type InvalidUnmarshalError struct {
@@ -216,6 +252,9 @@ type decodeState struct {
 	savedError            error
 	useNumber             bool
 	disallowUnknownFields bool
+	duplicateKeys         DuplicateKeyPolicy
+	caseSensitive         bool
+	strings               map[string]string // interned strings, nil unless InternStrings is used
 }
 
 // readIndex returns the position of the last byte read.
@@ -317,15 +356,20 @@ func (d *decodeState) scanWhile(op int) {
 // 这样外部基于之前的 off 就能拿到这个 primitive token
 //
 func (d *decodeState) rescanLiteral() {
+	if d.scan.syntax == JSON5Syntax {
+		// JSON5 literals don't have a fixed shape; let the scanner find the end.
+		d.scanWhile(scanContinue)
+		return
+	}
 	data, i := d.data, d.off
 Switch:
-	switch data[i-1] {
-	case '"': // string
+	switch quote := data[i-1]; quote {
+	case '"', '\'': // string
 		for ; i < len(data); i++ {
 			switch data[i] {
 			case '\\':
 				i++ // escaped char
-			case '"':
+			case quote:
 				i++ // tokenize the closing quote too
 				break Switch
 			}
@@ -673,6 +717,13 @@ func (d *decodeState) object(v reflect.Value) error {
 		origErrorContext = *d.errorContext
 	}
 
+	// Keys seen so far in this object, mapped to whether their values
+	// have already been collected into a []interface{}.
+	var seen map[string]bool
+	if d.duplicateKeys != LastKeyWins {
+		seen = make(map[string]bool)
+	}
+
 	for {
 		// Read opening " of string key or closing }.
 		d.scanWhile(scanSkipSpace)
@@ -688,7 +739,7 @@ func (d *decodeState) object(v reflect.Value) error {
 		start := d.readIndex()
 		d.rescanLiteral()
 		item := d.data[start:d.readIndex()]
-		key, ok := unquoteBytes(item)
+		key, ok := d.unquoteBytes(item)
 		if !ok {
 			panic(phasePanicMsg)
 		}
@@ -696,6 +747,7 @@ func (d *decodeState) object(v reflect.Value) error {
 		// Figure out field corresponding to key.
 		var subv reflect.Value
 		destring := false // whether the value is wrapped in a string to be decoded first
+		seenKey := string(key)
 
 		if v.Kind() == reflect.Map {
 			elemType := t.Elem()
@@ -710,7 +762,7 @@ func (d *decodeState) object(v reflect.Value) error {
 			if i, ok := fields.nameIndex[string(key)]; ok {
 				// Found an exact name match.
 				f = &fields.list[i]
-			} else {
+			} else if !d.caseSensitive {
 				// Fall back to the expensive case-insensitive
 				// linear search.
 				for i := range fields.list {
@@ -722,6 +774,9 @@ func (d *decodeState) object(v reflect.Value) error {
 				}
 			}
 			if f != nil {
+				// Keys that match the same field case-insensitively
+				// count as duplicates of each other.
+				seenKey = f.name
 				subv = v
 				destring = f.quoted
 				for _, i := range f.index {
@@ -756,6 +811,32 @@ func (d *decodeState) object(v reflect.Value) error {
 			}
 		}
 
+		// Apply the duplicate key policy. collect is set when the value
+		// must be appended to the values of earlier occurrences.
+		var collect, collected bool
+		var collectOld any
+		if seen != nil {
+			var dup bool
+			collected, dup = seen[seenKey]
+			switch {
+			case !dup:
+				seen[seenKey] = false
+			case d.duplicateKeys == CollectDuplicateKeys && subv.IsValid() &&
+				subv.Kind() == reflect.Interface && subv.NumMethod() == 0:
+				collect = true
+				seen[seenKey] = true
+				if v.Kind() == reflect.Struct {
+					collectOld = subv.Interface()
+				}
+			default:
+				if d.duplicateKeys != FirstKeyWins {
+					d.saveError(&DuplicateKeyError{Key: string(key), Offset: int64(start)})
+				}
+				subv = reflect.Value{}
+				destring = false
+			}
+		}
+
 		// Read : before value.
 		if d.opcode == scanSkipSpace {
 			d.scanWhile(scanSkipSpace)
@@ -783,10 +864,13 @@ func (d *decodeState) object(v reflect.Value) error {
 				return err
 			}
 		}
+		if collect && v.Kind() == reflect.Struct {
+			subv.Set(reflect.ValueOf(collectValues(collectOld, subv.Interface(), collected)))
+		}
 
 		// Write value back to map;
 		// if using struct, subv points into struct already.
-		if v.Kind() == reflect.Map {
+		if v.Kind() == reflect.Map && subv.IsValid() {
 			kt := t.Key()
 			var kv reflect.Value
 			switch {
@@ -797,7 +881,7 @@ func (d *decodeState) object(v reflect.Value) error {
 				}
 				kv = kv.Elem()
 			case kt.Kind() == reflect.String:
-				kv = reflect.ValueOf(key).Convert(kt)
+				kv = reflect.ValueOf(d.string(key)).Convert(kt)
 			default:
 				switch kt.Kind() {
 				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -821,6 +905,10 @@ func (d *decodeState) object(v reflect.Value) error {
 				}
 			}
 			if kv.IsValid() {
+				if collect {
+					old := v.MapIndex(kv).Interface()
+					subv.Set(reflect.ValueOf(collectValues(old, subv.Interface(), collected)))
+				}
 				v.SetMapIndex(kv, subv)
 			}
 		}
@@ -846,6 +934,16 @@ func (d *decodeState) object(v reflect.Value) error {
 	return nil
 }
 
+// collectValues appends val to the values already stored for a duplicate key.
+// If collected is false, old is the value of the first occurrence; otherwise
+// it is the []interface{} built by an earlier call.
+func collectValues(old, val any, collected bool) []any {
+	if collected {
+		return append(old.([]any), val)
+	}
+	return []any{old, val}
+}
+
 // convertNumber converts the number literal s to a float64 or a Number
 // depending on the setting of d.useNumber.
 func (d *decodeState) convertNumber(s string) (any, error) {
@@ -879,7 +977,7 @@ func (d *decodeState) literalStore(item []byte, v reflect.Value, fromQuoted bool
 		return u.UnmarshalJSON(item)
 	}
 	if ut != nil {
-		if item[0] != '"' {
+		if item[0] != '"' && item[0] != '\'' {
 			if fromQuoted {
 				d.saveError(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type()))
 				return nil
@@ -894,7 +992,7 @@ func (d *decodeState) literalStore(item []byte, v reflect.Value, fromQuoted bool
 			d.saveError(&UnmarshalTypeError{Value: val, Type: v.Type(), Offset: int64(d.readIndex())})
 			return nil
 		}
-		s, ok := unquoteBytes(item)
+		s, ok := d.unquoteBytes(item)
 		if !ok {
 			if fromQuoted {
 				return fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type())
@@ -944,8 +1042,8 @@ func (d *decodeState) literalStore(item []byte, v reflect.Value, fromQuoted bool
 			}
 		}
 
-	case '"': // string
-		s, ok := unquoteBytes(item)
+	case '"', '\'': // string
+		s, ok := d.unquoteBytes(item)
 		if !ok {
 			if fromQuoted {
 				return fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type())
@@ -971,17 +1069,22 @@ func (d *decodeState) literalStore(item []byte, v reflect.Value, fromQuoted bool
 			if v.Type() == numberType && !isValidNumber(string(s)) {
 				return fmt.Errorf("json: invalid number literal, trying to unmarshal %q into Number", item)
 			}
-			v.SetString(string(s))
+			v.SetString(d.string(s))
 		case reflect.Interface:
 			if v.NumMethod() == 0 {
-				v.Set(reflect.ValueOf(string(s)))
+				v.Set(reflect.ValueOf(d.string(s)))
 			} else {
 				d.saveError(&UnmarshalTypeError{Value: "string", Type: v.Type(), Offset: int64(d.readIndex())})
 			}
 		}
 
 	default: // number
-		if c != '-' && (c < '0' || c > '9') {
+		nonFinite := false
+		if d.scan.syntax == JSON5Syntax {
+			item = number5(item)
+			c, nonFinite = item[0], isNonFinite(item)
+		}
+		if c != '-' && (c < '0' || c > '9') && !nonFinite {
 			if fromQuoted {
 				return fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type())
 			}
@@ -1090,6 +1193,10 @@ func (d *decodeState) arrayInterface() []any {
 // objectInterface is like object but returns map[string]interface{}.
 func (d *decodeState) objectInterface() map[string]any {
 	m := make(map[string]any)
+	var seen map[string]bool
+	if d.duplicateKeys != LastKeyWins {
+		seen = make(map[string]bool)
+	}
 	for {
 		// Read opening " of string key or closing }.
 		d.scanWhile(scanSkipSpace)
@@ -1105,7 +1212,7 @@ func (d *decodeState) objectInterface() map[string]any {
 		start := d.readIndex()
 		d.rescanLiteral()
 		item := d.data[start:d.readIndex()]
-		key, ok := unquote(item)
+		key, ok := d.unquote(item)
 		if !ok {
 			panic(phasePanicMsg)
 		}
@@ -1120,7 +1227,21 @@ func (d *decodeState) objectInterface() map[string]any {
 		d.scanWhile(scanSkipSpace)
 
 		// Read value.
-		m[key] = d.valueInterface()
+		val := d.valueInterface()
+		if collected, dup := seen[key]; !dup {
+			if seen != nil {
+				seen[key] = false
+			}
+			m[key] = val
+		} else {
+			switch d.duplicateKeys {
+			case RejectDuplicateKeys:
+				d.saveError(&DuplicateKeyError{Key: key, Offset: int64(start)})
+			case CollectDuplicateKeys:
+				m[key] = collectValues(m[key], val, collected)
+				seen[key] = true
+			}
+		}
 
 		// Next token must be , or }.
 		if d.opcode == scanSkipSpace {
@@ -1153,15 +1274,20 @@ func (d *decodeState) literalInterface() any {
 	case 't', 'f': // true, false
 		return c == 't'
 
-	case '"': // string
-		s, ok := unquote(item)
+	case '"', '\'': // string
+		s, ok := d.unquote(item)
 		if !ok {
 			panic(phasePanicMsg)
 		}
 		return s
 
 	default: // number
-		if c != '-' && (c < '0' || c > '9') {
+		nonFinite := false
+		if d.scan.syntax == JSON5Syntax {
+			item = number5(item)
+			c, nonFinite = item[0], isNonFinite(item)
+		}
+		if c != '-' && (c < '0' || c > '9') && !nonFinite {
 			panic(phasePanicMsg)
 		}
 		n, err := d.convertNumber(string(item))
@@ -1204,7 +1330,47 @@ func unquote(s []byte) (t string, ok bool) {
 }
 
 func unquoteBytes(s []byte) (t []byte, ok bool) {
-	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+	return unquoteQuoted(s, '"')
+}
+
+// unquote is like the function unquote, but also accepts the
+// single-quoted strings of the relaxed syntaxes.
+func (d *decodeState) unquote(s []byte) (t string, ok bool) {
+	s, ok = d.unquoteBytes(s)
+	t = d.string(s)
+	return
+}
+
+// string returns s as a string. If string interning is enabled,
+// equal strings share the memory of the first one decoded.
+func (d *decodeState) string(s []byte) string {
+	if d.strings == nil {
+		return string(s)
+	}
+	if t, ok := d.strings[string(s)]; ok {
+		return t
+	}
+	t := string(s)
+	d.strings[t] = t
+	return t
+}
+
+// unquoteBytes is like the function unquoteBytes, but also accepts the
+// single-quoted strings of the relaxed syntaxes.
+func (d *decodeState) unquoteBytes(s []byte) (t []byte, ok bool) {
+	if d.scan.syntax == JSON5Syntax {
+		return unquote5(s)
+	}
+	if len(s) > 0 && s[0] == '\'' && d.scan.syntax != StrictSyntax {
+		return unquoteQuoted(s, '\'')
+	}
+	return unquoteQuoted(s, '"')
+}
+
+// unquoteQuoted converts a string literal delimited by quote into
+// the bytes it represents.
+func unquoteQuoted(s []byte, quote byte) (t []byte, ok bool) {
+	if len(s) < 2 || s[0] != quote || s[len(s)-1] != quote {
 		return
 	}
 	s = s[1 : len(s)-1]
@@ -1215,7 +1381,7 @@ func unquoteBytes(s []byte) (t []byte, ok bool) {
 	r := 0
 	for r < len(s) {
 		c := s[r]
-		if c == '\\' || c == '"' || c < ' ' {
+		if c == '\\' || c == quote || c < ' ' {
 			break
 		}
 		if c < utf8.RuneSelf {
@@ -1298,7 +1464,7 @@ func unquoteBytes(s []byte) (t []byte, ok bool) {
 			}
 
 		// Quote, control characters are invalid.
-		case c == '"', c < ' ':
+		case c == quote, c < ' ':
 			return
 
 		// ASCII
@@ -2968,3 +2968,81 @@ func TestStateTransition(t *testing.T) {
 	}
 	fmt.Printf("%+v", result)
 }
+
+func TestUnmarshalLimited(t *testing.T) {
+	tests := []struct {
+		in     string
+		limits Limits
+		err    error
+	}{
+		{in: `[[1]]`, limits: Limits{MaxDepth: 2}},
+		{in: `[[[1]]]`, limits: Limits{MaxDepth: 2}, err: &LimitError{Limit: "depth", Max: 2, Offset: 3}},
+		{in: `{"a":{"b":{}}}`, limits: Limits{MaxDepth: 2}, err: &LimitError{Limit: "depth", Max: 2, Offset: 11}},
+		{in: `"abcd"`, limits: Limits{MaxBytes: 6}},
+		{in: `"abcde"`, limits: Limits{MaxBytes: 6}, err: &LimitError{Limit: "bytes", Max: 6, Offset: 6}},
+		{in: `["abcd"]`, limits: Limits{MaxStringLen: 4}},
+		{in: `["abcde"]`, limits: Limits{MaxStringLen: 4}, err: &LimitError{Limit: "string length", Max: 4, Offset: 8}},
+		{in: `{"abcde":1}`, limits: Limits{MaxStringLen: 4}, err: &LimitError{Limit: "string length", Max: 4, Offset: 8}},
+		{in: `"aéé\n"`, limits: Limits{MaxStringLen: 6}, err: &LimitError{Limit: "string length", Max: 6, Offset: 9}},
+		{in: `[12345, -1e10]`, limits: Limits{MaxNumberLen: 5}},
+		{in: `[123456]`, limits: Limits{MaxNumberLen: 5}, err: &LimitError{Limit: "number length", Max: 5, Offset: 7}},
+		{in: `123456`, limits: Limits{MaxNumberLen: 5}, err: &LimitError{Limit: "number length", Max: 5, Offset: 6}},
+		{in: `[true, null, false]`, limits: Limits{MaxNumberLen: 1, MaxStringLen: 1}},
+	}
+	for _, tt := range tests {
+		var v any
+		err := UnmarshalLimited([]byte(tt.in), &v, tt.limits)
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("UnmarshalLimited(%#q, %+v) error %v, want %v", tt.in, tt.limits, err, tt.err)
+			continue
+		}
+		if err != nil && v != nil {
+			t.Errorf("UnmarshalLimited(%#q): wrote %v despite error", tt.in, v)
+		}
+	}
+}
+
+func TestUnmarshalRelaxedSyntax(t *testing.T) {
+	type T struct {
+		Name string
+		Tags []string
+		Text textUnmarshalerString
+	}
+	in := `{
+	// line comment
+	'Name': 'it\'s "quoted"', /* block
+	comment */ "Tags": ['a', "b",],
+	"Text": 'Hi',
+}`
+	var v T
+	if err := UnmarshalSyntax([]byte(in), &v, RelaxedSyntax); err != nil {
+		t.Fatalf("UnmarshalSyntax: %v", err)
+	}
+	want := T{Name: `it's "quoted"`, Tags: []string{"a", "b"}, Text: "hi"}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalSyntax = %+v, want %+v", v, want)
+	}
+
+	var a any
+	if err := UnmarshalSyntax([]byte(in), &a, RelaxedSyntax); err != nil {
+		t.Fatalf("UnmarshalSyntax into any: %v", err)
+	}
+	wantAny := map[string]any{"Name": `it's "quoted"`, "Tags": []any{"a", "b"}, "Text": "Hi"}
+	if !reflect.DeepEqual(a, wantAny) {
+		t.Errorf("UnmarshalSyntax into any = %v, want %v", a, wantAny)
+	}
+
+	if err := UnmarshalSyntax([]byte(in), &a, StrictSyntax); err == nil {
+		t.Errorf("UnmarshalSyntax with StrictSyntax: expected error")
+	}
+	if err := Unmarshal([]byte(`['a']`), &a); err == nil {
+		t.Errorf("Unmarshal of single-quoted string: expected error")
+	}
+
+	var s struct {
+		N int `json:",string"`
+	}
+	if err := UnmarshalSyntax([]byte(`{"N": '7',}`), &s, RelaxedSyntax); err != nil || s.N != 7 {
+		t.Errorf("UnmarshalSyntax with ,string = %d, %v", s.N, err)
+	}
+}
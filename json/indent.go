@@ -38,6 +38,7 @@ func compact(dst *bytes.Buffer, src []byte, escape bool) error {
 			dst.WriteByte(hex[src[i+2]&0xF])
 			start = i + 3
 		}
+		scan.bytes++
 		v := scan.step(scan, c)
 		if v >= scanSkipSpace {
 			if v == scanError {
@@ -0,0 +1,303 @@
+// JSON5 extensions to the scanner and the decoder, enabled by JSON5Syntax.
+
+package json
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// isIdentifierStart reports whether c can begin an identifier key.
+// Every byte of a non-ASCII character is accepted.
+func isIdentifierStart(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c == '$' || c >= utf8.RuneSelf
+}
+
+// stateInIdentifier is the state after reading the first byte of an
+// identifier key, such as after reading `{a`.
+func stateInIdentifier(s *scanner, c byte) int {
+	if isIdentifierStart(c) || '0' <= c && c <= '9' {
+		return scanContinue
+	}
+	return stateEndValue(s, c)
+}
+
+// beginWord starts scanning the literal word, whose first byte has been read.
+func (s *scanner) beginWord(word string) int {
+	s.word = word
+	s.wordLen = 1
+	s.step = stateWord
+	return scanBeginLiteral
+}
+
+// stateWord is the state after reading s.word[:s.wordLen] of
+// the literals Infinity and NaN.
+func stateWord(s *scanner, c byte) int {
+	if c == s.word[s.wordLen] {
+		s.wordLen++
+		if s.wordLen == len(s.word) {
+			s.step = stateEndValue
+		}
+		return scanContinue
+	}
+	return s.error(c, "in literal "+s.word+" (expecting "+quoteChar(s.word[s.wordLen])+")")
+}
+
+// stateSign5 is the state after reading `-` or `+` in JSON5, for the
+// continuations not allowed in JSON.
+func stateSign5(s *scanner, c byte) int {
+	switch c {
+	case '.':
+		s.step = stateLeadingDot
+		return scanContinue
+	case 'I':
+		s.beginWord("Infinity")
+		return scanContinue
+	case 'N':
+		s.beginWord("NaN")
+		return scanContinue
+	}
+	return s.error(c, "in numeric literal")
+}
+
+// stateLeadingDot is the state after reading a decimal point without
+// an integer part, such as after reading `.` or `-.`.
+func stateLeadingDot(s *scanner, c byte) int {
+	if '0' <= c && c <= '9' {
+		s.step = stateDot0
+		return scanContinue
+	}
+	return s.error(c, "after decimal point in numeric literal")
+}
+
+// stateZero5 is the state after reading `0` during a number in JSON5,
+// which may be the beginning of a hexadecimal number.
+func stateZero5(s *scanner, c byte) int {
+	if c == 'x' || c == 'X' {
+		s.step = stateHex
+		return scanContinue
+	}
+	return state0(s, c)
+}
+
+// stateHex is the state after reading `0x`.
+func stateHex(s *scanner, c byte) int {
+	if isHex(c) {
+		s.step = stateHex0
+		return scanContinue
+	}
+	return s.error(c, "in hexadecimal numeric literal")
+}
+
+// stateHex0 is the state after reading `0x` and at least one hex digit.
+func stateHex0(s *scanner, c byte) int {
+	if isHex(c) {
+		return scanContinue
+	}
+	return stateEndValue(s, c)
+}
+
+// stateInStringEsc5 is the state after reading `\` during a string, for
+// the escapes not allowed in JSON. Any character other than a digit
+// escapes itself; an escaped line terminator is a line continuation.
+func stateInStringEsc5(s *scanner, c byte) int {
+	switch {
+	case c == '0':
+		s.step = stateInStringEsc0
+	case c == 'x':
+		s.step = stateInStringEscX
+	case c == '\r':
+		s.step = stateInStringEscCR
+	case '1' <= c && c <= '9':
+		return s.error(c, "in string escape code")
+	default:
+		s.step = stateInString
+	}
+	return scanContinue
+}
+
+// stateInStringEsc0 is the state after reading `\0` during a string.
+func stateInStringEsc0(s *scanner, c byte) int {
+	if '0' <= c && c <= '9' {
+		return s.error(c, "after \\0 in string escape code")
+	}
+	return stateInString(s, c)
+}
+
+// stateInStringEscX is the state after reading `\x` during a string.
+func stateInStringEscX(s *scanner, c byte) int {
+	if isHex(c) {
+		s.step = stateInStringEscX1
+		return scanContinue
+	}
+	return s.error(c, "in \\x hexadecimal character escape")
+}
+
+// stateInStringEscX1 is the state after reading `\x1` during a string.
+func stateInStringEscX1(s *scanner, c byte) int {
+	if isHex(c) {
+		s.step = stateInString
+		return scanContinue
+	}
+	return s.error(c, "in \\x hexadecimal character escape")
+}
+
+// stateInStringEscCR is the state after reading `\` and a carriage return
+// during a string, which may be followed by a line feed.
+func stateInStringEscCR(s *scanner, c byte) int {
+	if c == '\n' {
+		s.step = stateInString
+		return scanContinue
+	}
+	return stateInString(s, c)
+}
+
+// unquote5 is like unquoteBytes for JSON5 string literals, either quote
+// and identifier keys, which are returned unchanged.
+func unquote5(s []byte) (t []byte, ok bool) {
+	if len(s) == 0 {
+		return
+	}
+	quote := s[0]
+	if quote != '"' && quote != '\'' {
+		return s, true
+	}
+	if len(s) < 2 || s[len(s)-1] != quote {
+		return
+	}
+	s = s[1 : len(s)-1]
+	if bytes.IndexAny(s, "\\\n\r") < 0 && bytes.IndexByte(s, quote) < 0 && utf8.Valid(s) {
+		return s, true
+	}
+
+	b := make([]byte, 0, len(s))
+	for r := 0; r < len(s); {
+		c := s[r]
+		if c == quote || c == '\n' || c == '\r' {
+			return
+		}
+		if c != '\\' {
+			rr, size := utf8.DecodeRune(s[r:])
+			b = utf8.AppendRune(b, rr)
+			r += size
+			continue
+		}
+		r++
+		if r >= len(s) {
+			return
+		}
+		switch c := s[r]; c {
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '0':
+			b = append(b, 0)
+		case 'x':
+			if r+2 >= len(s) || !isHex(s[r+1]) || !isHex(s[r+2]) {
+				return
+			}
+			b = utf8.AppendRune(b, rune(unhex(s[r+1])<<4|unhex(s[r+2])))
+			r += 2
+		case 'u':
+			rr := getu4(s[r-1:])
+			if rr < 0 {
+				return
+			}
+			r += 4
+			if utf16.IsSurrogate(rr) {
+				// A valid pair is consumed, anything else is replaced.
+				dec := utf16.DecodeRune(rr, getu4(s[r+1:]))
+				if dec != unicode.ReplacementChar {
+					r += 6
+				}
+				rr = dec
+			}
+			b = utf8.AppendRune(b, rr)
+		case '\r':
+			if r+1 < len(s) && s[r+1] == '\n' {
+				r++
+			}
+		case '\n':
+		default:
+			// Escaped U+2028 and U+2029 are line continuations too.
+			rr, size := utf8.DecodeRune(s[r:])
+			if rr != '\u2028' && rr != '\u2029' {
+				b = utf8.AppendRune(b, rr)
+			}
+			r += size - 1
+		}
+		r++
+	}
+	return b, true
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c <= 'F':
+		return c - 'A' + 10
+	}
+	return c - 'a' + 10
+}
+
+// isNonFinite reports whether the number literal item, as rewritten by
+// number5, is Infinity or NaN.
+func isNonFinite(item []byte) bool {
+	switch string(item) {
+	case "Infinity", "-Infinity", "NaN":
+		return true
+	}
+	return false
+}
+
+// number5 rewrites a JSON5 number literal as a JSON number, which is
+// what strconv and Number expect. Infinity and NaN are kept with their
+// sign, as strconv.ParseFloat accepts them.
+func number5(lit []byte) []byte {
+	s := string(lit)
+	sign := ""
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "-"):
+		sign, s = "-", s[1:]
+	}
+	switch {
+	case s == "":
+		return lit
+	case s == "NaN":
+		return []byte(s)
+	case s == "Infinity":
+		return []byte(sign + s)
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		n, ok := new(big.Int).SetString(s[2:], 16)
+		if !ok {
+			return lit
+		}
+		return []byte(sign + n.String())
+	case strings.HasPrefix(s, "."):
+		s = "0" + s
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && (i+1 == len(s) || s[i+1] < '0' || s[i+1] > '9') {
+		s = s[:i] + s[i+1:]
+	}
+	return []byte(sign + s)
+}
@@ -0,0 +1,36 @@
+package json
+
+import "strconv"
+
+// Limits bounds the resources spent decoding untrusted input.
+// A zero field means that dimension is not limited, except for MaxDepth,
+// which then falls back to the built-in nesting limit of 10000.
+//
+// Limits is shared by UnmarshalLimited, Decoder.SetLimits and the
+// gjson parser, so a single configuration can guard every entry point.
+type Limits struct {
+	MaxDepth     int   // maximum nesting depth of arrays and objects
+	MaxBytes     int64 // maximum size in bytes of one top-level JSON value
+	MaxStringLen int   // maximum encoded length of a string literal, excluding quotes
+	MaxNumberLen int   // maximum length of a number literal
+}
+
+// A LimitError reports that the input exceeded one of the configured Limits.
+type LimitError struct {
+	Limit  string // the limit that was exceeded: "depth", "bytes", "string length" or "number length"
+	Max    int64  // the configured maximum
+	Offset int64  // error occurred after reading Offset bytes
+}
+
+func (e *LimitError) Error() string {
+	return "json: " + e.Limit + " exceeds limit of " + strconv.FormatInt(e.Max, 10) +
+		" at offset " + strconv.FormatInt(e.Offset, 10)
+}
+
+// UnmarshalLimited is like Unmarshal but rejects input that exceeds limits
+// with a *LimitError. The size of data is checked before anything is parsed,
+// the remaining limits are checked while data is scanned for well-formedness,
+// so no part of v is written when a limit is exceeded.
+func UnmarshalLimited(data []byte, v any, limits Limits) error {
+	return UnmarshalWith(data, v, WithLimits(limits))
+}
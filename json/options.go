@@ -0,0 +1,71 @@
+package json
+
+// A DecodeOption configures a call to UnmarshalWith.
+type DecodeOption func(*decodeState)
+
+// UseNumber makes UnmarshalWith unmarshal a number into an interface{}
+// as a Number instead of as a float64, like Decoder.UseNumber.
+func UseNumber() DecodeOption {
+	return func(d *decodeState) { d.useNumber = true }
+}
+
+// DisallowUnknownFields makes UnmarshalWith return an error when the
+// destination is a struct and the input contains object keys which do not
+// match any non-ignored, exported fields in the destination,
+// like Decoder.DisallowUnknownFields.
+func DisallowUnknownFields() DecodeOption {
+	return func(d *decodeState) { d.disallowUnknownFields = true }
+}
+
+// DuplicateKeys sets the policy applied when an object contains the same
+// key more than once, like Decoder.SetDuplicateKeys.
+func DuplicateKeys(policy DuplicateKeyPolicy) DecodeOption {
+	return func(d *decodeState) { d.duplicateKeys = policy }
+}
+
+// CaseSensitive makes object keys match struct fields only when they are
+// exactly equal to the field name, instead of preferring an exact match
+// but also accepting a case-insensitive one, like Decoder.CaseSensitive.
+func CaseSensitive() DecodeOption {
+	return func(d *decodeState) { d.caseSensitive = true }
+}
+
+// WithLimits makes UnmarshalWith reject input that exceeds limits with a
+// *LimitError, like UnmarshalLimited.
+func WithLimits(limits Limits) DecodeOption {
+	return func(d *decodeState) { d.scan.setLimits(limits) }
+}
+
+// WithSyntax makes UnmarshalWith accept input written in syntax,
+// like UnmarshalSyntax.
+func WithSyntax(syntax Syntax) DecodeOption {
+	return func(d *decodeState) { d.scan.setSyntax(syntax) }
+}
+
+// InternStrings makes UnmarshalWith decode equal strings, including
+// object keys, into strings sharing the same memory. It saves memory when
+// the input repeats the same keys or values many times, at the cost of a
+// map lookup for every string. Strings are only shared within one call.
+func InternStrings() DecodeOption {
+	return func(d *decodeState) { d.strings = make(map[string]string) }
+}
+
+// UnmarshalWith is like Unmarshal but applies opts, which make the
+// settings otherwise available only on a Decoder usable without one.
+// Later options override earlier ones.
+func UnmarshalWith(data []byte, v any, opts ...DecodeOption) error {
+	var d decodeState
+	for _, opt := range opts {
+		opt(&d)
+	}
+	if max := d.scan.limits.MaxBytes; max > 0 && int64(len(data)) > max {
+		return &LimitError{Limit: "bytes", Max: max, Offset: max}
+	}
+	err := checkValid(data, &d.scan)
+	if err != nil {
+		return err
+	}
+
+	d.init(data)
+	return d.unmarshal(v)
+}
@@ -0,0 +1,86 @@
+package json
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestUnmarshalWith(t *testing.T) {
+	type T struct {
+		ID   int
+		Name string
+	}
+	tests := []struct {
+		in   string
+		ptr  any
+		opts []DecodeOption
+		out  any
+		err  error
+	}{
+		{in: `{"a": 1.5}`, ptr: new(any), out: map[string]any{"a": 1.5}},
+		{in: `{"a": 1.5}`, ptr: new(any), opts: []DecodeOption{UseNumber()}, out: map[string]any{"a": Number("1.5")}},
+		{in: `{"id": 1, "extra": 2}`, ptr: new(T), out: T{ID: 1}},
+		{in: `{"id": 1, "extra": 2}`, ptr: new(T), opts: []DecodeOption{DisallowUnknownFields()}, out: T{ID: 1}, err: errors.New(`json: unknown field "extra"`)},
+		{in: `{"ID": 1, "ID": 2}`, ptr: new(T), out: T{ID: 2}},
+		{in: `{"ID": 1, "ID": 2}`, ptr: new(T), opts: []DecodeOption{DuplicateKeys(FirstKeyWins)}, out: T{ID: 1}},
+		{in: `{"ID": 1, "id": 2}`, ptr: new(T), opts: []DecodeOption{DuplicateKeys(RejectDuplicateKeys)}, out: T{ID: 1}, err: &DuplicateKeyError{Key: "id", Offset: 10}},
+		{in: `{"id": 1, "NAME": "x"}`, ptr: new(T), opts: []DecodeOption{CaseSensitive()}, out: T{}},
+		{in: `{"ID": 1, "id": 2}`, ptr: new(T), opts: []DecodeOption{CaseSensitive(), DuplicateKeys(RejectDuplicateKeys)}, out: T{ID: 1}},
+		{in: `{"ID": 1, "id": 2}`, ptr: new(T), opts: []DecodeOption{CaseSensitive(), DisallowUnknownFields()}, out: T{ID: 1}, err: errors.New(`json: unknown field "id"`)},
+		{in: `[[1]]`, ptr: new(any), opts: []DecodeOption{WithLimits(Limits{MaxDepth: 1})}, err: &LimitError{Limit: "depth", Max: 1, Offset: 2}},
+		{in: `[1, 2]`, ptr: new(any), opts: []DecodeOption{WithLimits(Limits{MaxBytes: 5})}, err: &LimitError{Limit: "bytes", Max: 5, Offset: 5}},
+		{in: `{ID: 0x10, Name: 'x',}`, ptr: new(T), opts: []DecodeOption{WithSyntax(JSON5Syntax)}, out: T{ID: 16, Name: "x"}},
+		{in: `{"Name": "x", "ID": 1}`, ptr: new(T), opts: []DecodeOption{InternStrings()}, out: T{ID: 1, Name: "x"}},
+	}
+	for i, tt := range tests {
+		v := reflect.New(reflect.TypeOf(tt.ptr).Elem())
+		err := UnmarshalWith([]byte(tt.in), v.Interface(), tt.opts...)
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: UnmarshalWith(%#q) error %v, want %v", i, tt.in, err, tt.err)
+			continue
+		}
+		if tt.out != nil && !reflect.DeepEqual(v.Elem().Interface(), tt.out) {
+			t.Errorf("#%d: UnmarshalWith(%#q) = %#v, want %#v", i, tt.in, v.Elem().Interface(), tt.out)
+		}
+	}
+}
+
+func TestInternStrings(t *testing.T) {
+	data := []byte(`[{"kind": "user", "tags": {"kind": "user"}}, {"kind": "user"}]`)
+	var v []map[string]any
+	if err := UnmarshalWith(data, &v, InternStrings()); err != nil {
+		t.Fatalf("UnmarshalWith error %v", err)
+	}
+	var strs []string
+	for _, m := range v {
+		for k, e := range m {
+			strs = append(strs, k)
+			if s, ok := e.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+	}
+	strs = append(strs, v[0]["tags"].(map[string]any)["kind"].(string))
+	data0 := func(s string) uintptr { return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data }
+	for _, s := range strs {
+		if s == "kind" && data0(s) != data0(strs[0]) {
+			t.Errorf("key %q is not interned", s)
+		}
+		if s == "user" && data0(s) != data0(v[1]["kind"].(string)) {
+			t.Errorf("value %q is not interned", s)
+		}
+	}
+
+	var typed []struct {
+		Kind string
+		Tags map[string]string
+	}
+	if err := UnmarshalWith(data, &typed, InternStrings()); err != nil {
+		t.Fatalf("UnmarshalWith error %v", err)
+	}
+	if data0(typed[0].Kind) != data0(typed[1].Kind) || data0(typed[0].Kind) != data0(typed[0].Tags["kind"]) {
+		t.Errorf("struct fields and map values are not interned")
+	}
+}
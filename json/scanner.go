@@ -31,7 +31,7 @@ func checkValid(data []byte, scan *scanner) error {
 	scan.reset()
 	for _, c := range data {
 		scan.bytes++
-		if scan.step(scan, c) == scanError {
+		if scan.stepLimited(c) == scanError {
 			return scan.err
 		}
 	}
@@ -80,6 +80,33 @@ type scanner struct {
 	// total bytes consumed, updated by decoder.Decode (and deliberately
 	// not set to zero by scan.reset)
 	bytes int64
+
+	// Configured limits, kept across scan.reset. limited reports whether
+	// stepLimited has to track literal lengths at all.
+	limits  Limits
+	limited bool
+
+	// First byte and length so far of the literal being scanned,
+	// maintained by stepLimited.
+	litKind byte
+	litLen  int
+
+	// Accepted dialect, kept across scan.reset like the limits.
+	syntax Syntax
+
+	// Closing quote of the string being scanned.
+	quote byte
+
+	// While inside a comment, the state to return to after it and the
+	// opcode reported for each of its bytes. openComment reports whether
+	// the input cannot end here because of an unfinished comment.
+	resume      func(*scanner, byte) int
+	commentOp   int
+	openComment bool
+
+	// JSON5 literal word being scanned and how much of it has been read.
+	word    string
+	wordLen int
 }
 
 var scannerPool = sync.Pool{
@@ -96,6 +123,17 @@ func newScanner() *scanner {
 	return scan
 }
 
+// setLimits configures the limits enforced by pushParseState and stepLimited.
+func (s *scanner) setLimits(limits Limits) {
+	s.limits = limits
+	s.limited = limits.MaxStringLen > 0 || limits.MaxNumberLen > 0
+}
+
+// setSyntax configures the dialect accepted by the scanner.
+func (s *scanner) setSyntax(syntax Syntax) {
+	s.syntax = syntax
+}
+
 func freeScanner(scan *scanner) {
 	// Avoid hanging on to too much memory in extreme cases.
 	if len(scan.parseState) > 1024 {
@@ -150,6 +188,7 @@ func (s *scanner) reset() {
 	s.parseState = s.parseState[0:0]
 	s.err = nil
 	s.endTop = false
+	s.openComment = false
 }
 
 // eof tells the scanner that the end of input has been reached.
@@ -158,6 +197,10 @@ func (s *scanner) eof() int {
 	if s.err != nil {
 		return scanError
 	}
+	if s.openComment {
+		s.err = &SyntaxError{"unexpected end of JSON input", s.bytes}
+		return scanError
+	}
 	if s.endTop {
 		return scanEnd
 	}
@@ -175,12 +218,50 @@ func (s *scanner) eof() int {
 // an error state is returned if maxNestingDepth was exceeded, otherwise successState is returned.
 func (s *scanner) pushParseState(c byte, newParseState int, successState int) int {
 	s.parseState = append(s.parseState, newParseState)
+	if s.limits.MaxDepth > 0 && len(s.parseState) > s.limits.MaxDepth {
+		return s.limitError("depth", int64(s.limits.MaxDepth))
+	}
 	if len(s.parseState) <= maxNestingDepth {
 		return successState
 	}
 	return s.error(c, "exceeded max depth")
 }
 
+// stepLimited is like s.step but also enforces the literal length limits.
+// Every byte of a literal after the first one is reported as scanContinue,
+// so counting those is enough to know the length of the literal so far.
+func (s *scanner) stepLimited(c byte) int {
+	op := s.step(s, c)
+	if !s.limited {
+		return op
+	}
+	switch op {
+	case scanBeginLiteral:
+		s.litKind, s.litLen = c, 1
+		if n := len(s.parseState); n > 0 && s.parseState[n-1] == parseObjectKey && c != '"' && c != '\'' {
+			// Count a JSON5 identifier key like a string,
+			// as if both quotes had been read.
+			s.litKind, s.litLen = '"', 3
+		}
+	case scanContinue:
+		s.litLen++
+		switch s.litKind {
+		case '"', '\'':
+			// Both quotes are counted, so this catches an overlong
+			// string at the latest on its closing quote.
+			if max := s.limits.MaxStringLen; max > 0 && s.litLen-2 > max {
+				return s.limitError("string length", int64(max))
+			}
+		case 't', 'f', 'n':
+		default:
+			if max := s.limits.MaxNumberLen; max > 0 && s.litLen > max {
+				return s.limitError("number length", int64(max))
+			}
+		}
+	}
+	return op
+}
+
 // popParseState pops a parse state (already obtained) off the stack
 // and updates s.step accordingly.
 func (s *scanner) popParseState() {
@@ -199,11 +280,20 @@ func isSpace(c byte) bool {
 	return c <= ' ' && (c == ' ' || c == '\t' || c == '\r' || c == '\n')
 }
 
+// isSpace is like the isSpace function but also accepts the vertical tab
+// and form feed that JSON5 allows as white space.
+func (s *scanner) isSpace(c byte) bool {
+	return isSpace(c) || s.syntax == JSON5Syntax && (c == '\v' || c == '\f')
+}
+
 // stateBeginValueOrEmpty is the state after reading `[`.
 func stateBeginValueOrEmpty(s *scanner, c byte) int {
-	if isSpace(c) {
+	if s.isSpace(c) {
 		return scanSkipSpace
 	}
+	if c == '/' && s.syntax != StrictSyntax {
+		return s.beginComment(stateBeginValueOrEmpty, scanSkipSpace)
+	}
 	if c == ']' {
 		return stateEndValue(s, c)
 	}
@@ -212,9 +302,41 @@ func stateBeginValueOrEmpty(s *scanner, c byte) int {
 
 // stateBeginValue is the state at the beginning of the input.
 func stateBeginValue(s *scanner, c byte) int {
-	if isSpace(c) {
+	if s.isSpace(c) {
 		return scanSkipSpace
 	}
+	if s.syntax != StrictSyntax {
+		switch c {
+		case '/':
+			return s.beginComment(stateBeginValue, scanSkipSpace)
+		case ']':
+			// Trailing comma in an array.
+			if n := len(s.parseState); n > 0 && s.parseState[n-1] == parseArrayValue {
+				return stateEndValue(s, c)
+			}
+		case '\'':
+			s.step = stateInString
+			s.quote = c
+			return scanBeginLiteral
+		}
+	}
+	if s.syntax == JSON5Syntax {
+		switch c {
+		case '+':
+			s.step = stateNeg
+			return scanBeginLiteral
+		case '.':
+			s.step = stateLeadingDot
+			return scanBeginLiteral
+		case '0':
+			s.step = stateZero5
+			return scanBeginLiteral
+		case 'I':
+			return s.beginWord("Infinity")
+		case 'N':
+			return s.beginWord("NaN")
+		}
+	}
 	switch c {
 	case '{':
 		s.step = stateBeginStringOrEmpty
@@ -224,6 +346,7 @@ func stateBeginValue(s *scanner, c byte) int {
 		return s.pushParseState(c, parseArrayValue, scanBeginArray)
 	case '"':
 		s.step = stateInString
+		s.quote = c
 		return scanBeginLiteral
 	case '-':
 		s.step = stateNeg
@@ -250,9 +373,12 @@ func stateBeginValue(s *scanner, c byte) int {
 
 // stateBeginStringOrEmpty is the state after reading `{`.
 func stateBeginStringOrEmpty(s *scanner, c byte) int {
-	if isSpace(c) {
+	if s.isSpace(c) {
 		return scanSkipSpace
 	}
+	if c == '/' && s.syntax != StrictSyntax {
+		return s.beginComment(stateBeginStringOrEmpty, scanSkipSpace)
+	}
 	if c == '}' {
 		n := len(s.parseState)
 		s.parseState[n-1] = parseObjectValue
@@ -263,11 +389,30 @@ func stateBeginStringOrEmpty(s *scanner, c byte) int {
 
 // stateBeginString is the state after reading `{"key": value,`.
 func stateBeginString(s *scanner, c byte) int {
-	if isSpace(c) {
+	if s.isSpace(c) {
 		return scanSkipSpace
 	}
 	if c == '"' {
 		s.step = stateInString
+		s.quote = c
+		return scanBeginLiteral
+	}
+	if s.syntax != StrictSyntax {
+		switch c {
+		case '/':
+			return s.beginComment(stateBeginString, scanSkipSpace)
+		case '}':
+			// Trailing comma in an object.
+			s.parseState[len(s.parseState)-1] = parseObjectValue
+			return stateEndValue(s, c)
+		case '\'':
+			s.step = stateInString
+			s.quote = c
+			return scanBeginLiteral
+		}
+	}
+	if s.syntax == JSON5Syntax && isIdentifierStart(c) {
+		s.step = stateInIdentifier
 		return scanBeginLiteral
 	}
 	return s.error(c, "looking for beginning of object key string")
@@ -284,10 +429,13 @@ func stateEndValue(s *scanner, c byte) int {
 		s.endTop = true
 		return stateEndTop(s, c)
 	}
-	if isSpace(c) {
+	if s.isSpace(c) {
 		s.step = stateEndValue
 		return scanSkipSpace
 	}
+	if c == '/' && s.syntax != StrictSyntax {
+		return s.beginComment(stateEndValue, scanSkipSpace)
+	}
 	ps := s.parseState[n-1]
 	switch ps {
 	case parseObjectKey:
@@ -326,7 +474,10 @@ func stateEndValue(s *scanner, c byte) int {
 // such as after reading `{}` or `[1,2,3]`.
 // Only space characters should be seen now.
 func stateEndTop(s *scanner, c byte) int {
-	if !isSpace(c) {
+	if c == '/' && s.syntax != StrictSyntax {
+		return s.beginComment(stateEndTop, scanEnd)
+	}
+	if !s.isSpace(c) {
 		// Complain about non-space byte on next call.
 		s.error(c, "after top-level value")
 	}
@@ -335,7 +486,7 @@ func stateEndTop(s *scanner, c byte) int {
 
 // stateInString is the state after reading `"`.
 func stateInString(s *scanner, c byte) int {
-	if c == '"' {
+	if c == s.quote {
 		s.step = stateEndValue
 		return scanContinue
 	}
@@ -343,7 +494,7 @@ func stateInString(s *scanner, c byte) int {
 		s.step = stateInStringEsc
 		return scanContinue
 	}
-	if c < 0x20 {
+	if c < 0x20 && (s.syntax != JSON5Syntax || c == '\n' || c == '\r') {
 		return s.error(c, "in string literal")
 	}
 	return scanContinue
@@ -358,6 +509,14 @@ func stateInStringEsc(s *scanner, c byte) int {
 	case 'u':
 		s.step = stateInStringEscU
 		return scanContinue
+	case '\'':
+		if s.syntax != StrictSyntax {
+			s.step = stateInString
+			return scanContinue
+		}
+	}
+	if s.syntax == JSON5Syntax {
+		return stateInStringEsc5(s, c)
 	}
 	return s.error(c, "in string escape code")
 }
@@ -406,12 +565,18 @@ func stateInStringEscU123(s *scanner, c byte) int {
 func stateNeg(s *scanner, c byte) int {
 	if c == '0' {
 		s.step = state0
+		if s.syntax == JSON5Syntax {
+			s.step = stateZero5
+		}
 		return scanContinue
 	}
 	if '1' <= c && c <= '9' {
 		s.step = state1
 		return scanContinue
 	}
+	if s.syntax == JSON5Syntax {
+		return stateSign5(s, c)
+	}
 	return s.error(c, "in numeric literal")
 }
 
@@ -445,6 +610,10 @@ func stateDot(s *scanner, c byte) int {
 		s.step = stateDot0
 		return scanContinue
 	}
+	if s.syntax == JSON5Syntax {
+		// JSON5 allows a trailing decimal point, such as in `1.` or `1.e5`.
+		return stateDot0(s, c)
+	}
 	return s.error(c, "after decimal point in numeric literal")
 }
 
@@ -581,6 +750,60 @@ func stateNul(s *scanner, c byte) int {
 	return s.error(c, "in literal null (expecting 'l')")
 }
 
+// beginComment is called on the `/` starting a comment in state resume.
+// Every byte of the comment is reported as op, which is what resume
+// reports for a space.
+func (s *scanner) beginComment(resume func(*scanner, byte) int, op int) int {
+	s.step = stateComment
+	s.resume = resume
+	s.commentOp = op
+	s.openComment = true
+	return op
+}
+
+// stateComment is the state after reading the `/` starting a comment.
+func stateComment(s *scanner, c byte) int {
+	switch c {
+	case '/':
+		s.step = stateLineComment
+		s.openComment = false
+		return s.commentOp
+	case '*':
+		s.step = stateBlockComment
+		return s.commentOp
+	}
+	return s.error(c, "looking for beginning of comment")
+}
+
+// stateLineComment is the state inside a `//` comment.
+func stateLineComment(s *scanner, c byte) int {
+	if c == '\n' {
+		s.step = s.resume
+	}
+	return s.commentOp
+}
+
+// stateBlockComment is the state inside a `/*` comment.
+func stateBlockComment(s *scanner, c byte) int {
+	if c == '*' {
+		s.step = stateBlockCommentStar
+	}
+	return s.commentOp
+}
+
+// stateBlockCommentStar is the state after reading `*` inside a `/*` comment.
+func stateBlockCommentStar(s *scanner, c byte) int {
+	switch c {
+	case '/':
+		s.step = s.resume
+		s.openComment = false
+	case '*':
+	default:
+		s.step = stateBlockComment
+	}
+	return s.commentOp
+}
+
 // stateError is the state after reaching a syntax error,
 // such as after reading `[1}` or `5.1.2`.
 func stateError(s *scanner, c byte) int {
@@ -594,6 +817,13 @@ func (s *scanner) error(c byte, context string) int {
 	return scanError
 }
 
+// limitError records a LimitError and switches to the error state.
+func (s *scanner) limitError(limit string, max int64) int {
+	s.step = stateError
+	s.err = &LimitError{Limit: limit, Max: max, Offset: s.bytes}
+	return scanError
+}
+
 // quoteChar formats c as a quoted character literal
 func quoteChar(c byte) string {
 	// special cases - different from quoted strings
@@ -32,6 +32,134 @@ func TestValid(t *testing.T) {
 	}
 }
 
+var validSyntaxTests = []struct {
+	data    string
+	strict  bool
+	relaxed bool
+	json5   bool
+}{
+	{`{"a":[1,"x"]}`, true, true, true},
+	{`// comment
+	{"a": 1}`, false, true, true},
+	{`{"a": /* inline */ 1} // trailing`, false, true, true},
+	{`[1/**/,2]`, false, true, true},
+	{`1/*x*/`, false, true, true},
+	{`/**/`, false, false, false},
+	{`// only a comment`, false, false, false},
+	{`[1] /* unterminated`, false, false, false},
+	{`[1] /`, false, false, false},
+	{`[1 / 2]`, false, false, false},
+	{`[1, 2,]`, false, true, true},
+	{`{"a": 1,}`, false, true, true},
+	{`[1,,]`, false, false, false},
+	{`[,]`, false, false, false},
+	{`{,}`, false, false, false},
+	{`{"a":,}`, false, false, false},
+	{`'single'`, false, true, true},
+	{`{'a': 'b"c', "d": 'e\'f'}`, false, true, true},
+	{`"a\'b"`, false, true, true},
+	{`'a"`, false, false, false},
+	{`'a
+b'`, false, false, false},
+
+	// JSON5
+	{`{a: 1, $b_2: 2, _: 3, ключ: 4}`, false, false, true},
+	{`{a/**/: 1}`, false, false, true},
+	{`{2a: 1}`, false, false, false},
+	{`{a-b: 1}`, false, false, false},
+	{`[a]`, false, false, false},
+	{`[0x1F, 0XaB, -0x0, +0x10]`, false, false, true},
+	{`0x`, false, false, false},
+	{`0xG`, false, false, false},
+	{`10x1`, false, false, false},
+	{`[.5, 5., -.5e3, 5.e3, +1]`, false, false, true},
+	{`.`, false, false, false},
+	{`-.`, false, false, false},
+	{`.e3`, false, false, false},
+	{`01`, false, false, false},
+	{`+`, false, false, false},
+	{`[Infinity, -Infinity, +Infinity, NaN, -NaN]`, false, false, true},
+	{`Infinit`, false, false, false},
+	{`infinity`, false, false, false},
+	{`Nan`, false, false, false},
+	{`"a\
+b"`, false, false, true},
+	{"'a\\\r\nb\\\rc'", false, false, true},
+	{`"\x41\v\0\q\ \€"`, false, false, true},
+	{`"\01"`, false, false, false},
+	{`"\1"`, false, false, false},
+	{`"\x4"`, false, false, false},
+	{"\"tab\there\"", false, false, true},
+	{"\"cr\rhere\"", false, false, false},
+}
+
+func TestValidSyntax(t *testing.T) {
+	for _, tt := range validSyntaxTests {
+		if ok := ValidSyntax([]byte(tt.data), StrictSyntax); ok != tt.strict {
+			t.Errorf("ValidSyntax(%#q, StrictSyntax) = %v, want %v", tt.data, ok, tt.strict)
+		}
+		if ok := ValidSyntax([]byte(tt.data), RelaxedSyntax); ok != tt.relaxed {
+			t.Errorf("ValidSyntax(%#q, RelaxedSyntax) = %v, want %v", tt.data, ok, tt.relaxed)
+		}
+		if ok := ValidSyntax([]byte(tt.data), JSON5Syntax); ok != tt.json5 {
+			t.Errorf("ValidSyntax(%#q, JSON5Syntax) = %v, want %v", tt.data, ok, tt.json5)
+		}
+		if ok := Valid([]byte(tt.data)); ok != tt.strict {
+			t.Errorf("Valid(%#q) = %v, want %v", tt.data, ok, tt.strict)
+		}
+	}
+}
+
+var json5Tests = []struct {
+	data string
+	want any
+}{
+	{`{a: 1, $b_2: 'x', ключ: true,}`, map[string]any{"a": float64(1), "$b_2": "x", "ключ": true}},
+	{`[0x1F, -0xff, +7, .5, 5., -.5e1, 5.e1]`, []any{float64(31), float64(-255), float64(7), 0.5, float64(5), -5.0, float64(50)}},
+	{`"a\
+b\x41\v\0\q"`, "ab\x41\v\x00q"},
+	{`'\u00e9\ud83d\ude00'`, "é😀"},
+	{"'tab\there'", "tab\there"},
+}
+
+func TestUnmarshalJSON5(t *testing.T) {
+	for _, tt := range json5Tests {
+		var v any
+		if err := UnmarshalSyntax([]byte(tt.data), &v, JSON5Syntax); err != nil {
+			t.Errorf("UnmarshalSyntax(%#q): %v", tt.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(v, tt.want) {
+			t.Errorf("UnmarshalSyntax(%#q) = %#v, want %#v", tt.data, v, tt.want)
+		}
+	}
+
+	var special []float64
+	if err := UnmarshalSyntax([]byte(`[Infinity, -Infinity, NaN]`), &special, JSON5Syntax); err != nil {
+		t.Fatalf("UnmarshalSyntax: %v", err)
+	}
+	if !math.IsInf(special[0], 1) || !math.IsInf(special[1], -1) || !math.IsNaN(special[2]) {
+		t.Errorf("UnmarshalSyntax = %v, want [+Inf -Inf NaN]", special)
+	}
+
+	var s struct {
+		N   int
+		U   uint8
+		Num Number
+		Map map[int]string
+	}
+	in := `{N: 0x10, U: +0xff, Num: .5, Map: {'1': 'x'}}`
+	if err := UnmarshalSyntax([]byte(in), &s, JSON5Syntax); err != nil {
+		t.Fatalf("UnmarshalSyntax(%#q): %v", in, err)
+	}
+	if s.N != 16 || s.U != 255 || s.Num != "0.5" || s.Map[1] != "x" {
+		t.Errorf("UnmarshalSyntax(%#q) = %+v", in, s)
+	}
+	if err := UnmarshalSyntax([]byte(`{N: Infinity}`), &s, JSON5Syntax); err == nil {
+		t.Errorf("UnmarshalSyntax of Infinity into int: expected error")
+	}
+}
+
 // Tests of simple examples.
 
 type example struct {
@@ -201,6 +329,15 @@ func TestIndentErrors(t *testing.T) {
 	}
 }
 
+func TestCompactErrors(t *testing.T) {
+	for i, tt := range indentErrorTests {
+		var buf bytes.Buffer
+		if err := Compact(&buf, []byte(tt.in)); !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: Compact: %#v", i, err)
+		}
+	}
+}
+
 func diff(t *testing.T, a, b []byte) {
 	for i := 0; ; i++ {
 		if i >= len(a) || i >= len(b) || a[i] != b[i] {
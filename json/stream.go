@@ -22,6 +22,8 @@ type Decoder struct {
 
 	tokenState int
 	tokenStack []int
+
+	maxBytes int64 // Limits.MaxBytes, applied to each value read
 }
 
 // NewDecoder returns a new decoder that reads from r.
@@ -41,6 +43,24 @@ func (dec *Decoder) UseNumber() { dec.d.useNumber = true }
 // non-ignored, exported fields in the destination.
 func (dec *Decoder) DisallowUnknownFields() { dec.d.disallowUnknownFields = true }
 
+// CaseSensitive causes the Decoder to match object keys to struct fields
+// only when they are exactly equal to the field name, so that keys differing
+// only in case are treated as unknown fields instead of aliases.
+func (dec *Decoder) CaseSensitive() { dec.d.caseSensitive = true }
+
+// SetDuplicateKeys sets the policy the Decoder applies when an object
+// contains the same key more than once. The default is LastKeyWins.
+func (dec *Decoder) SetDuplicateKeys(policy DuplicateKeyPolicy) { dec.d.duplicateKeys = policy }
+
+// SetLimits makes the Decoder reject values that exceed limits with a
+// *LimitError. MaxBytes applies to each value read by Decode, and
+// stops reading from the input as soon as it is exceeded.
+func (dec *Decoder) SetLimits(limits Limits) {
+	dec.scan.setLimits(limits)
+	dec.d.scan.setLimits(limits)
+	dec.maxBytes = limits.MaxBytes
+}
+
 // Decode reads the next JSON-encoded value from its
 // input and stores it in the value pointed to by v.
 //
@@ -91,6 +111,10 @@ func (dec *Decoder) readValue() (int, error) {
 
 	scanp := dec.scanp
 	var err error
+	// Whether anything but space or comments has been seen, and if so
+	// the offset of the value's first byte relative to dec.scanp.
+	started := false
+	start := 0
 Input:
 	// help the compiler see that scanp is never negative, so it can remove
 	// some bounds checks below.
@@ -100,7 +124,8 @@ Input:
 		for ; scanp < len(dec.buf); scanp++ {
 			c := dec.buf[scanp]
 			dec.scan.bytes++
-			switch dec.scan.step(&dec.scan, c) {
+			switch dec.scan.stepLimited(c) {
+			case scanSkipSpace:
 			case scanEnd:
 				// scanEnd is delayed one byte so we decrement
 				// the scanner bytes count by 1 to ensure that
@@ -118,6 +143,15 @@ Input:
 			case scanError:
 				dec.err = dec.scan.err
 				return 0, dec.scan.err
+			default:
+				if !started {
+					started = true
+					start = scanp - dec.scanp
+				}
+			}
+			if dec.maxBytes > 0 && started && int64(scanp+1-dec.scanp-start) > dec.maxBytes {
+				dec.err = &LimitError{Limit: "bytes", Max: dec.maxBytes, Offset: dec.scan.bytes}
+				return 0, dec.err
 			}
 		}
 
@@ -128,7 +162,7 @@ Input:
 				if dec.scan.step(&dec.scan, ' ') == scanEnd {
 					break Input
 				}
-				if nonSpace(dec.buf) {
+				if started || dec.scan.openComment {
 					err = io.ErrUnexpectedEOF
 				}
 			}
@@ -168,15 +202,6 @@ func (dec *Decoder) refill() error {
 	return err
 }
 
-func nonSpace(b []byte) bool {
-	for _, c := range b {
-		if !isSpace(c) {
-			return true
-		}
-	}
-	return false
-}
-
 // An Encoder writes JSON values to an output stream.
 type Encoder struct {
 	w          io.Writer
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -223,6 +224,158 @@ func TestDecoderBuffered(t *testing.T) {
 	}
 }
 
+func TestDecoderCaseSensitive(t *testing.T) {
+	type T struct {
+		ID    int
+		Admin bool `json:"admin"`
+	}
+	in := `{"ID": 1, "Admin": true} {"id": 2, "admin": true}`
+	dec := NewDecoder(strings.NewReader(in))
+	dec.CaseSensitive()
+	var v T
+	if err := dec.Decode(&v); err != nil || v != (T{ID: 1}) {
+		t.Fatalf("Decode = %+v, %v, want %+v", v, err, T{ID: 1})
+	}
+	v = T{}
+	if err := dec.Decode(&v); err != nil || v != (T{Admin: true}) {
+		t.Fatalf("Decode = %+v, %v, want %+v", v, err, T{Admin: true})
+	}
+
+	// Keys differing only in case are unknown fields, not aliases
+	// that could override a validated value.
+	dec = NewDecoder(strings.NewReader(`{"admin": false, "ADMIN": true}`))
+	dec.CaseSensitive()
+	dec.DisallowUnknownFields()
+	v = T{}
+	if err := dec.Decode(&v); err == nil || err.Error() != `json: unknown field "ADMIN"` || v.Admin {
+		t.Fatalf("Decode = %+v, %v, want unknown field error", v, err)
+	}
+}
+
+func TestDecoderSyntax(t *testing.T) {
+	in := `/* header */ {'a': [1, 2,]} // first
+	2/* second */'three' // last`
+	dec := NewDecoder(strings.NewReader(in))
+	dec.SetSyntax(RelaxedSyntax)
+	want := []any{map[string]any{"a": []any{float64(1), float64(2)}}, float64(2), "three"}
+	for i, w := range want {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode #%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(v, w) {
+			t.Fatalf("Decode #%d = %v, want %v", i, v, w)
+		}
+	}
+	var v any
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("Decode after last value: %v, want io.EOF", err)
+	}
+
+	dec = NewDecoder(strings.NewReader(`1 /* unterminated`))
+	dec.SetSyntax(RelaxedSyntax)
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if err := dec.Decode(&v); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Decode in comment: %v, want io.ErrUnexpectedEOF", err)
+	}
+
+	dec = NewDecoder(strings.NewReader(`{key: 0x10,} -Infinity .5`))
+	dec.SetSyntax(JSON5Syntax)
+	var s struct{ Key int }
+	var f, g float64
+	if err := dec.Decode(&s); err != nil || s.Key != 16 {
+		t.Fatalf("Decode = %+v, %v", s, err)
+	}
+	if err := dec.Decode(&f); err != nil || !math.IsInf(f, -1) {
+		t.Fatalf("Decode = %v, %v", f, err)
+	}
+	if err := dec.Decode(&g); err != nil || g != 0.5 {
+		t.Fatalf("Decode = %v, %v", g, err)
+	}
+}
+
+func TestDecoderDuplicateKeys(t *testing.T) {
+	type S struct {
+		ID  int
+		Any any
+	}
+	tests := []struct {
+		in     string
+		policy DuplicateKeyPolicy
+		ptr    any
+		out    any
+		err    error
+	}{
+		{in: `{"a":1,"a":2}`, policy: LastKeyWins, ptr: new(any), out: map[string]any{"a": float64(2)}},
+		{in: `{"a":1,"a":2}`, policy: FirstKeyWins, ptr: new(any), out: map[string]any{"a": float64(1)}},
+		{in: `{"a":1,"b":3,"a":2}`, policy: RejectDuplicateKeys, ptr: new(any), out: map[string]any{"a": float64(1), "b": float64(3)},
+			err: &DuplicateKeyError{Key: "a", Offset: 13}},
+		{in: `{"a":1,"a":[2],"a":"3"}`, policy: CollectDuplicateKeys, ptr: new(any),
+			out: map[string]any{"a": []any{float64(1), []any{float64(2)}, "3"}}},
+		{in: `{"a":1,"a":2}`, policy: FirstKeyWins, ptr: new(map[string]int), out: map[string]int{"a": 1}},
+		{in: `{"a":1,"a":2,"a":3}`, policy: CollectDuplicateKeys, ptr: new(map[string]any),
+			out: map[string]any{"a": []any{float64(1), float64(2), float64(3)}}},
+		{in: `{"a":1,"a":2}`, policy: CollectDuplicateKeys, ptr: new(map[string]int), out: map[string]int{"a": 1},
+			err: &DuplicateKeyError{Key: "a", Offset: 7}},
+		{in: `{"ID":1,"id":2}`, policy: FirstKeyWins, ptr: new(S), out: S{ID: 1}},
+		{in: `{"ID":1,"id":2}`, policy: RejectDuplicateKeys, ptr: new(S), out: S{ID: 1},
+			err: &DuplicateKeyError{Key: "id", Offset: 8}},
+		{in: `{"Any":true,"Any":null}`, policy: CollectDuplicateKeys, ptr: new(S), out: S{Any: []any{true, nil}}},
+	}
+	for i, tt := range tests {
+		dec := NewDecoder(strings.NewReader(tt.in))
+		dec.SetDuplicateKeys(tt.policy)
+		err := dec.Decode(tt.ptr)
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("#%d: %s: error %v, want %v", i, tt.in, err, tt.err)
+		}
+		if got := reflect.ValueOf(tt.ptr).Elem().Interface(); !reflect.DeepEqual(got, tt.out) {
+			t.Errorf("#%d: %s: have %#v, want %#v", i, tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[1, 2] {"a": [[]]} "long string" 7`))
+	dec.SetLimits(Limits{MaxDepth: 2, MaxBytes: 12})
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	err := dec.Decode(&v)
+	if want := (&LimitError{Limit: "depth", Max: 2, Offset: 15}); !reflect.DeepEqual(err, want) {
+		t.Fatalf("Decode error %v, want %v", err, want)
+	}
+
+	// MaxBytes stops reading before the whole value has been seen.
+	dec = NewDecoder(strings.NewReader(`7 "long string" 8`))
+	dec.SetLimits(Limits{MaxBytes: 12})
+	if err := dec.Decode(&v); err != nil || v != float64(7) {
+		t.Fatalf("Decode = %v, %v", v, err)
+	}
+	err = dec.Decode(&v)
+	if want := (&LimitError{Limit: "bytes", Max: 12, Offset: 15}); !reflect.DeepEqual(err, want) {
+		t.Fatalf("Decode error %v, want %v", err, want)
+	}
+
+	// Space and comments before a value do not count towards MaxBytes.
+	dec = NewDecoder(strings.NewReader("[1]" + strings.Repeat(" ", 20) + "[1,2] /* comment */ [1,2,3,4]"))
+	dec.SetLimits(Limits{MaxBytes: 7})
+	dec.SetSyntax(RelaxedSyntax)
+	for i, want := range []any{[]any{float64(1)}, []any{float64(1), float64(2)}} {
+		if err := dec.Decode(&v); err != nil || !reflect.DeepEqual(v, want) {
+			t.Fatalf("Decode #%d = %v, %v, want %v", i, v, err, want)
+		}
+	}
+	err = dec.Decode(&v)
+	if want := (&LimitError{Limit: "bytes", Max: 7, Offset: 51}); !reflect.DeepEqual(err, want) {
+		t.Fatalf("Decode error %v, want %v", err, want)
+	}
+}
+
 func nlines(s string, n int) string {
 	if n <= 0 {
 		return ""
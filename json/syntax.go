@@ -0,0 +1,53 @@
+package json
+
+// Syntax selects the JSON dialect accepted when decoding.
+// Every dialect is a superset of the previous one.
+type Syntax int
+
+const (
+	// StrictSyntax accepts only JSON as defined by RFC 8259.
+	// It is the default for Unmarshal, Decoder and the gjson parser.
+	StrictSyntax Syntax = iota
+
+	// RelaxedSyntax additionally accepts // line and /* block */ comments
+	// wherever whitespace is allowed, a trailing comma after the last
+	// element of an array or object, and strings delimited by single quotes.
+	RelaxedSyntax
+
+	// JSON5Syntax additionally accepts the rest of JSON5 (https://json5.org):
+	// identifiers as object keys, hexadecimal numbers, numbers with a leading
+	// or trailing decimal point or a leading plus sign, Infinity and NaN,
+	// and the JavaScript string escapes including line continuations.
+	// White space may also be a vertical tab or a form feed.
+	// Non-ASCII characters are accepted in identifiers without checking their
+	// Unicode category, and \u escapes are not supported outside of strings.
+	// The non-ASCII white space of JSON5 (U+00A0, U+2028, U+2029, U+FEFF and
+	// the other characters in the Unicode Zs category) is not supported.
+	//
+	// Numbers are decoded as if written in JSON where possible: 0x1F is
+	// decoded as 31 and .5 as 0.5, also into a Number.
+	JSON5Syntax
+)
+
+// UnmarshalSyntax is like Unmarshal but accepts input written in syntax.
+// Comments and other extensions are passed through unchanged to
+// Unmarshaler implementations found along the way.
+func UnmarshalSyntax(data []byte, v any, syntax Syntax) error {
+	return UnmarshalWith(data, v, WithSyntax(syntax))
+}
+
+// ValidSyntax reports whether data is a valid encoding in syntax.
+func ValidSyntax(data []byte, syntax Syntax) bool {
+	scan := newScanner()
+	defer freeScanner(scan)
+	scan.setSyntax(syntax)
+	defer scan.setSyntax(StrictSyntax)
+	return checkValid(data, scan) == nil
+}
+
+// SetSyntax makes the Decoder accept input written in syntax.
+// Token and More only understand StrictSyntax.
+func (dec *Decoder) SetSyntax(syntax Syntax) {
+	dec.scan.setSyntax(syntax)
+	dec.d.scan.setSyntax(syntax)
+}
@@ -0,0 +1,148 @@
+package gjson
+
+import (
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"gjson/json"
+)
+
+// json5 判断是否使用 json.JSON5Syntax
+func (t *parser) json5() bool {
+	return t.syntax == json.JSON5Syntax
+}
+
+// isNumberStart5 判断 c 是否是只有 JSON5 才允许的数字开头：+1、.5、Infinity、NaN
+func isNumberStart5(c rune) bool {
+	return c == '+' || c == '.' || c == 'I' || c == 'N'
+}
+
+// passHex 跳过十六进制数字 0x 之后的部分，调用时 t.index 指向 'x'
+func (t *parser) passHex() {
+	t.next()
+	if !isHex(t.curChar()) {
+		t.fail("hexadecimal digit in number")
+	}
+	for isHex(t.curChar()) {
+		t.next()
+	}
+}
+
+func isHex(c rune) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// normalizeNumber 把 JSON5 的数字字面量改写成 JSON 的数字，这样 strconv 和 Number 都能直接使用：
+// 0x1F 改写为 31，.5 改写为 0.5，5. 改写为 5，去掉开头的 '+'。
+// Infinity 和 NaN 没有对应的 JSON 数字，保留原样，strconv.ParseFloat 可以解析它们
+func normalizeNumber(lit string) string {
+	s, sign := lit, ""
+	switch s[0] {
+	case '+':
+		s = s[1:]
+	case '-':
+		s, sign = s[1:], "-"
+	}
+	switch {
+	case s == "NaN":
+		return s
+	case s == "Infinity":
+		return sign + s
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		n, _ := new(big.Int).SetString(s[2:], 16)
+		return sign + n.String()
+	case s[0] == '.':
+		s = "0" + s
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && (i+1 == len(s) || !isDigit(rune(s[i+1]))) {
+		s = s[:i] + s[i+1:]
+	}
+	return sign + s
+}
+
+// isIdentifierKey 判断当前位置是否是 JSON5 中不带引号的 object key
+func (t *parser) isIdentifierKey() bool {
+	return t.json5() && t.valueChar() != '"'
+}
+
+// skipIdentifier 跳过当前位置作为 object key 的标识符，返回它在 t.data 中的范围。
+// 非 ASCII 字符不检查 Unicode 类别，和 json 包的行为一致；不支持标识符中的 \u 转义
+func (t *parser) skipIdentifier() (start, end int) {
+	start = t.index
+	for !t.end() {
+		r, size := utf8.DecodeRune(t.data[t.index:])
+		if !isIdentifierRune(r, t.index == start) {
+			break
+		}
+		t.index += size
+	}
+	if t.index == start {
+		t.fail("object key string")
+	}
+	return start, t.index
+}
+
+// isIdentifierRune 判断 r 能否出现在标识符中，first 表示是否是第一个字符，数字不能作为开头
+func isIdentifierRune(r rune, first bool) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '_', r == '$':
+		return true
+	case r < utf8.RuneSelf:
+		return !first && isDigit(r)
+	}
+	return r != utf8.RuneError
+}
+
+// parseEscape5 解析只有 JSON5 才允许的转义，调用时 t.index 指向 '\' 之后的字符。
+// 除了数字以外的字符都可以转义为它本身，转义的换行是续行，不产生任何字符
+func (t *parser) parseEscape5(b []byte) []byte {
+	switch c := t.curChar(); {
+	case c == 'v':
+		b = append(b, '\v')
+	case c == '0':
+		if t.index+1 < t.len && isDigit(rune(t.data[t.index+1])) {
+			t.failAt(t.index+1, "non-digit after \\0 in string")
+		}
+		b = append(b, 0)
+	case c == 'x':
+		t.next()
+		var r rune
+		for i := 0; i < 2; i++ {
+			c := t.curChar()
+			if !isHex(c) {
+				t.fail("hexadecimal digit in \\x escape")
+			}
+			r = r*16 + hexValue(c)
+			t.next()
+		}
+		return utf8.AppendRune(b, r)
+	case c == '\r':
+		if t.index+1 < t.len && t.data[t.index+1] == '\n' {
+			t.next()
+		}
+	case c == '\n':
+	case isDigit(c), c == eof:
+		t.fail("valid escape character")
+	default:
+		// 转义的 U+2028、U+2029 同样是续行
+		r, size := utf8.DecodeRune(t.data[t.index:])
+		if r != '\u2028' && r != '\u2029' {
+			b = utf8.AppendRune(b, r)
+		}
+		t.index += size
+		return b
+	}
+	t.next()
+	return b
+}
+
+func hexValue(c rune) rune {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c <= 'F':
+		return c - 'A' + 10
+	}
+	return c - 'a' + 10
+}
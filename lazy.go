@@ -0,0 +1,244 @@
+package gjson
+
+import (
+	"strconv"
+
+	"gjson/json"
+)
+
+// Lazy 引用原始输入中的一个 JSON 值，只保存它在输入中的范围，访问时才扫描、解码。
+// ParseLazy 已经检查过整个输入，所以 Get、Index 等方法只需要跳过不需要的值，
+// 不需要解码的访问不会分配内存。零值表示不存在的值。
+// Lazy 直接引用 ParseLazy 的输入，使用期间不能修改输入。
+type Lazy struct {
+	data       []byte
+	start, end int
+}
+
+// ParseLazy 检查 data 是否为合法的 JSON，并返回引用整个值的 Lazy。
+// opts 中的 Limits、RejectInvalidUTF8 和 DuplicateKeys(json.RejectDuplicateKeys) 在检查时生效，
+// 其他选项在 Decode、Unmarshal 时传入。拒绝重复的 key 需要记录每个 object 的所有 key，检查时会分配内存。
+func ParseLazy(data []byte, opts ...Option) (l Lazy, err error) {
+	// 没有选项时 parser 可以分配在栈上
+	p := parser{data: data, len: len(data)}
+	if len(opts) > 0 {
+		p = *newParser(data, opts...)
+		// Lazy 的访问方法只认识严格语法，也不能跳过容错模式记录下来的错误
+		p.syntax = json.StrictSyntax
+		p.tolerant = false
+	}
+	defer p.recover(&err)
+	p.checkSize()
+
+	p.passBlank()
+	start := p.index
+	if p.duplicateKeys == json.RejectDuplicateKeys {
+		// 数字保存为 Number，这样 savedError 只可能是重复的 key
+		p.numberMode = NumberString
+		p.parseValue()
+	} else {
+		p.skipValue()
+	}
+	end := p.index
+	p.passBlank()
+	if !p.end() {
+		p.fail("end of input")
+	}
+	if p.savedError != nil {
+		return Lazy{}, p.savedError
+	}
+	return Lazy{data: data, start: start, end: end}, nil
+}
+
+// parser 返回一个扫描 l 的 parser，l 已经通过了检查，扫描时不会出错
+func (l Lazy) parser() parser {
+	return parser{data: l.data, len: l.end, index: l.start}
+}
+
+// Kind 根据第一个字符返回值的类型
+func (l Lazy) Kind() Kind {
+	if l.data == nil {
+		return KindMissing
+	}
+	switch l.data[l.start] {
+	case '{':
+		return KindObject
+	case '[':
+		return KindArray
+	case '"':
+		return KindString
+	case 't', 'f':
+		return KindBool
+	case 'n':
+		return KindNull
+	}
+	return KindNumber
+}
+
+// Exists 在值存在时返回 true，JSON null 也是存在的值
+func (l Lazy) Exists() bool {
+	return l.data != nil
+}
+
+// Raw 返回值在输入中的原始文本，和输入共用内存
+func (l Lazy) Raw() []byte {
+	if l.data == nil {
+		return nil
+	}
+	return l.data[l.start:l.end]
+}
+
+// Span 返回值在输入中的范围 [start, end)
+func (l Lazy) Span() (start, end int) {
+	return l.start, l.end
+}
+
+// Get 返回 object 中 key 对应的值，有重复的 key 时返回最后一个，和 Value.Get 以及默认策略下的
+// Decode、Unmarshal、Query 相同，所以总是会扫描整个 object。
+// 没有转义的 key 直接和原始文本比较，不需要分配内存。
+func (l Lazy) Get(key string) Lazy {
+	if l.Kind() != KindObject {
+		return Lazy{}
+	}
+	var found Lazy
+	l.forEach(func(k, v Lazy) bool {
+		if k.equal(key) {
+			found = v
+		}
+		return true
+	})
+	return found
+}
+
+// equal 比较 l 表示的字符串和 s，l 必须是字符串
+func (l Lazy) equal(s string) bool {
+	p := l.parser()
+	start, end, decode := p.skipString()
+	if !decode {
+		return string(l.data[start:end]) == s
+	}
+	return l.String() == s
+}
+
+// Index 返回 array 中下标为 i 的元素，l 不是 array 或者下标越界时返回不存在的值
+func (l Lazy) Index(i int) Lazy {
+	if l.Kind() != KindArray || i < 0 {
+		return Lazy{}
+	}
+	var found Lazy
+	l.forEach(func(_, v Lazy) bool {
+		if i == 0 {
+			found = v
+			return false
+		}
+		i--
+		return true
+	})
+	return found
+}
+
+// Len 返回 array 的元素个数或者 object 的成员个数，其他类型返回 0
+func (l Lazy) Len() int {
+	switch l.Kind() {
+	case KindArray, KindObject:
+	default:
+		return 0
+	}
+	n := 0
+	l.forEach(func(_, _ Lazy) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// ForEach 依次对 array 的元素或者 object 的成员调用 fn，fn 返回 false 时停止。
+// object 的 key 是一个字符串 Lazy，array 的 key 是不存在的值，其他类型不会调用 fn。
+func (l Lazy) ForEach(fn func(key, value Lazy) bool) {
+	switch l.Kind() {
+	case KindArray, KindObject:
+		l.forEach(fn)
+	}
+}
+
+func (l Lazy) forEach(fn func(key, value Lazy) bool) {
+	if l.data == nil {
+		return
+	}
+	p := l.parser()
+	closing := rune(']')
+	if p.curChar() == '{' {
+		closing = '}'
+	}
+	p.next()
+	p.passBlank()
+	if p.curChar() == closing {
+		return
+	}
+	for {
+		var key Lazy
+		if closing == '}' {
+			start := p.index
+			p.skipString()
+			key = Lazy{data: l.data, start: start, end: p.index}
+			p.expect(':', "':' after object key")
+			p.passBlank()
+		}
+		start := p.index
+		p.skipValue()
+		if !fn(key, Lazy{data: l.data, start: start, end: p.index}) {
+			return
+		}
+		if p.passComma(closing) {
+			return
+		}
+	}
+}
+
+// String 返回字符串解码后的内容，其他类型返回原始文本，不存在的值返回空字符串。
+// 只有字符串中有转义时才需要解码，但转换为 string 总是需要分配内存。
+func (l Lazy) String() string {
+	if l.Kind() != KindString {
+		return string(l.Raw())
+	}
+	p := l.parser()
+	return p.parseString()
+}
+
+// Bool 返回 bool 值，不是 bool 时返回 false
+func (l Lazy) Bool() bool {
+	return l.Kind() == KindBool && l.data[l.start] == 't'
+}
+
+// Int 返回数字的整数部分，不是数字时返回 0，超出 int64 范围时返回最接近的 int64
+func (l Lazy) Int() int64 {
+	if l.Kind() != KindNumber {
+		return 0
+	}
+	if i, err := strconv.ParseInt(string(l.Raw()), 10, 64); err == nil {
+		return i
+	}
+	return floatToInt(l.Float())
+}
+
+// Float 返回数字的 float64 值，不是数字时返回 0
+func (l Lazy) Float() float64 {
+	if l.Kind() != KindNumber {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(string(l.Raw()), 64)
+	return f
+}
+
+// Decode 解码整个值，opts 和 Unmarshal 相同
+func (l Lazy) Decode(opts ...Option) (Value, error) {
+	if l.data == nil {
+		return Value{}, nil
+	}
+	return ParseValue(l.Raw(), opts...)
+}
+
+// Unmarshal 把值解码到 v，规则和 Unmarshal 相同
+func (l Lazy) Unmarshal(v any, opts ...Option) error {
+	return Unmarshal(l.Raw(), v, opts...)
+}
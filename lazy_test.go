@@ -0,0 +1,144 @@
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestLazy(t *testing.T) {
+	data := []byte(` {"name": "gjson", "name2": "x\ty", "stars": 1024, "ratio": -1.5e1,
+		"ok": true, "none": null, "tags": ["json", "parser", []], "owner": {"id": 7}} `)
+	l, err := ParseLazy(data)
+	if err != nil {
+		t.Fatalf("ParseLazy: %v", err)
+	}
+	if start, end := l.Span(); start != 1 || end != len(data)-1 {
+		t.Fatalf("Span %d, %d", start, end)
+	}
+	if l.Kind() != KindObject || l.Len() != 8 {
+		t.Fatalf("Kind %v, Len %d", l.Kind(), l.Len())
+	}
+	if s := l.Get("name").String(); s != "gjson" {
+		t.Errorf("name %q", s)
+	}
+	// 有转义的 key 和值需要解码
+	if s := l.Get("name2").String(); s != "x\ty" {
+		t.Errorf("name2 %q", s)
+	}
+	if raw := l.Get("name2").Raw(); string(raw) != `"x\ty"` {
+		t.Errorf("name2 raw %q", raw)
+	}
+	if i := l.Get("stars").Int(); i != 1024 {
+		t.Errorf("stars %d", i)
+	}
+	if i, f := l.Get("ratio").Int(), l.Get("ratio").Float(); i != -15 || f != -15 {
+		t.Errorf("ratio %d, %v", i, f)
+	}
+	if !l.Get("ok").Bool() || l.Get("none").Kind() != KindNull {
+		t.Errorf("ok %v, none %v", l.Get("ok").Bool(), l.Get("none").Kind())
+	}
+	if s := l.Get("tags").Index(1).String(); s != "parser" {
+		t.Errorf("tags[1] %q", s)
+	}
+	if n := l.Get("tags").Index(2).Len(); n != 0 {
+		t.Errorf("tags[2] len %d", n)
+	}
+	if l.Get("tags").Index(3).Exists() || l.Get("missing").Exists() || l.Get("name").Get("x").Exists() {
+		t.Errorf("missing values exist")
+	}
+	if i := l.Get("owner").Get("id").Int(); i != 7 {
+		t.Errorf("owner.id %d", i)
+	}
+
+	var keys []string
+	l.ForEach(func(key, _ Lazy) bool {
+		keys = append(keys, key.String())
+		return len(keys) < 3
+	})
+	if !reflect.DeepEqual(keys, []string{"name", "name2", "stars"}) {
+		t.Errorf("ForEach keys %q", keys)
+	}
+
+	v, err := l.Get("tags").Decode()
+	if err != nil || !reflect.DeepEqual(v.Interface(), []any{"json", "parser", []any(nil)}) {
+		t.Errorf("Decode %v, %v", v.Interface(), err)
+	}
+	var owner struct{ ID int }
+	if err := l.Get("owner").Unmarshal(&owner); err != nil || owner.ID != 7 {
+		t.Errorf("Unmarshal %+v, %v", owner, err)
+	}
+}
+
+func TestLazyScalarLen(t *testing.T) {
+	for _, data := range []string{`1`, `"abc"`, `true`, `null`} {
+		l, err := ParseLazy([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseLazy(%q): %v", data, err)
+		}
+		if n := l.Len(); n != 0 {
+			t.Errorf("ParseLazy(%q).Len() = %d, expected 0", data, n)
+		}
+	}
+	if n := (Lazy{}).Len(); n != 0 {
+		t.Errorf("Lazy{}.Len() = %d, expected 0", n)
+	}
+}
+
+func TestLazyDuplicateKeys(t *testing.T) {
+	data := []byte(`{"role": "user", "n": 1, "role": "admin"}`)
+	l, err := ParseLazy(data)
+	if err != nil {
+		t.Fatalf("ParseLazy: %v", err)
+	}
+	v, err := l.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if s := l.Get("role").String(); s != "admin" || v.Get("role").String() != s {
+		t.Fatalf("Get = %q, Decode().Get = %q", s, v.Get("role").String())
+	}
+
+	l, err = ParseLazy(data, DuplicateKeys(json.RejectDuplicateKeys))
+	if want := (&json.DuplicateKeyError{Key: "role", Offset: 25}); !reflect.DeepEqual(err, want) || l.Exists() {
+		t.Fatalf("ParseLazy with RejectDuplicateKeys = %v, expected %v", err, want)
+	}
+	if _, err := ParseLazy([]byte(`{"a": {"b": 1, "c": 1e400}}`), DuplicateKeys(json.RejectDuplicateKeys)); err != nil {
+		t.Fatalf("ParseLazy with RejectDuplicateKeys: %v", err)
+	}
+}
+
+func TestLazyErrors(t *testing.T) {
+	for _, data := range []string{`{"a": [1, 2,]}`, `[1] 2`, `"abc`, ``} {
+		l, err := ParseLazy([]byte(data))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || l.Exists() {
+			t.Errorf("ParseLazy(%q) = %v, %v, expected syntax error", data, l.Raw(), err)
+		}
+	}
+	// 检查 RejectDuplicateKeys 时会解析整个值，容错模式不能吞掉语法错误
+	l, err := ParseLazy([]byte(`{"a":[1, x], "b":2}`), Tolerant(), DuplicateKeys(json.RejectDuplicateKeys))
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || l.Exists() {
+		t.Errorf("ParseLazy with Tolerant and RejectDuplicateKeys = %v, %v, expected syntax error", l.Raw(), err)
+	}
+}
+
+func TestLazyAllocs(t *testing.T) {
+	data := []byte(`{"users": [{"name": "alice", "age": 30}, {"name": "bob", "age": 25, "admin": true}]}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		l, err := ParseLazy(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		user := l.Get("users").Index(1)
+		if user.Get("age").Int() != 25 || !user.Get("admin").Bool() || l.Get("users").Len() != 2 {
+			t.Fatal("unexpected value")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("lazy access allocates %v times, expected 0", allocs)
+	}
+}
@@ -0,0 +1,57 @@
+package gjson
+
+import "gjson/json"
+
+// maxNestingDepth 是没有设置 MaxDepth 时的默认嵌套深度限制，和 json 包相同
+const maxNestingDepth = 10000
+
+// Limits 设置解析不可信输入时的资源限制，和 json.UnmarshalLimited、json.Decoder.SetLimits 共用同一个配置。
+// 超出限制时返回 *json.LimitError，其中 Offset 和 json 包相同，是发现超出限制时已经读取的字节数。
+func Limits(limits json.Limits) Option {
+	return func(t *parser) {
+		t.limits = limits
+	}
+}
+
+// limitError 抛出 *json.LimitError，由 recover 转换为返回值
+func (t *parser) limitError(limit string, max int64, offset int) {
+	panic(&json.LimitError{Limit: limit, Max: max, Offset: int64(offset)})
+}
+
+// checkSize 在解析开始前检查输入的总字节数
+func (t *parser) checkSize() {
+	if max := t.limits.MaxBytes; max > 0 && int64(t.len) > max {
+		t.limitError("bytes", max, int(max))
+	}
+}
+
+// enter 在进入 array 或 object 时调用，检查嵌套深度，离开时需要调用 leave
+func (t *parser) enter() {
+	t.depth++
+	max := t.limits.MaxDepth
+	if max <= 0 {
+		max = maxNestingDepth
+	}
+	if t.depth > max {
+		t.limitError("depth", int64(max), t.index+1)
+	}
+}
+
+func (t *parser) leave() {
+	t.depth--
+}
+
+// checkString 检查从 start 开始、到 t.index 为止的字符串内容是否超出长度限制。
+// 和 json 包一样把两个引号计算在内，所以内容只比 max 多一个字节时在结束的引号处报错，没有结束的引号时是语法错误
+func (t *parser) checkString(start int) {
+	if max := t.limits.MaxStringLen; max > 0 && t.index-start > max && (t.index-start > max+1 || !t.end()) {
+		t.limitError("string length", int64(max), start+max+2)
+	}
+}
+
+// checkNumber 检查从 start 开始、到 t.index 为止的数字是否超出长度限制
+func (t *parser) checkNumber(start int) {
+	if max := t.limits.MaxNumberLen; max > 0 && t.index-start > max {
+		t.limitError("number length", int64(max), start+max+1)
+	}
+}
@@ -0,0 +1,61 @@
+package gjson
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		data   string
+		limits json.Limits
+		err    error
+	}{
+		{`[[1]]`, json.Limits{MaxDepth: 2}, nil},
+		{`[[[1]]]`, json.Limits{MaxDepth: 2}, &json.LimitError{Limit: "depth", Max: 2, Offset: 3}},
+		{`{"a": {"b": {}}}`, json.Limits{MaxDepth: 2}, &json.LimitError{Limit: "depth", Max: 2, Offset: 13}},
+		{`"abcd"`, json.Limits{MaxBytes: 6}, nil},
+		{`"abcde"`, json.Limits{MaxBytes: 6}, &json.LimitError{Limit: "bytes", Max: 6, Offset: 6}},
+		{`["abcd"]`, json.Limits{MaxStringLen: 4}, nil},
+		{`["abcde"]`, json.Limits{MaxStringLen: 4}, &json.LimitError{Limit: "string length", Max: 4, Offset: 8}},
+		{`["abcdefg"]`, json.Limits{MaxStringLen: 4}, &json.LimitError{Limit: "string length", Max: 4, Offset: 8}},
+		{`{"abc\nd": 1}`, json.Limits{MaxStringLen: 5}, &json.LimitError{Limit: "string length", Max: 5, Offset: 9}},
+		{`[12345, -1e10]`, json.Limits{MaxNumberLen: 5}, nil},
+		{`[123456]`, json.Limits{MaxNumberLen: 5}, &json.LimitError{Limit: "number length", Max: 5, Offset: 7}},
+		{`[-1234567]`, json.Limits{MaxNumberLen: 5}, &json.LimitError{Limit: "number length", Max: 5, Offset: 7}},
+		{strings.Repeat("[", maxNestingDepth+1), json.Limits{}, &json.LimitError{Limit: "depth", Max: maxNestingDepth, Offset: maxNestingDepth + 1}},
+	}
+	for _, tt := range tests {
+		var result any
+		err := Unmarshal([]byte(tt.data), &result, Limits(tt.limits))
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("Unmarshal(%.20q, %+v) error %v, expected %v", tt.data, tt.limits, err, tt.err)
+		}
+
+		// 反射赋值使用同样的限制
+		var typed []any
+		err = Unmarshal([]byte(tt.data), &typed, Limits(tt.limits))
+		if le, ok := err.(*json.LimitError); ok != (tt.err != nil) || ok && !reflect.DeepEqual(le, tt.err) {
+			t.Errorf("Unmarshal(%.20q) into []any error %v, expected %v", tt.data, err, tt.err)
+		}
+
+		// Offset 的含义和 json 包相同；json 包默认的深度限制报告的是语法错误
+		if tt.limits != (json.Limits{}) {
+			var expected any
+			if err := json.UnmarshalLimited([]byte(tt.data), &expected, tt.limits); !reflect.DeepEqual(err, tt.err) {
+				t.Errorf("json.UnmarshalLimited(%.20q, %+v) error %v, expected %v", tt.data, tt.limits, err, tt.err)
+			}
+		}
+	}
+
+	// 内容只比限制长一个字节并且没有结束的引号时，和 json 包一样是语法错误
+	var result any
+	if err := Unmarshal([]byte(`["abcde`), &result, Limits(json.Limits{MaxStringLen: 4})); err == nil {
+		t.Errorf("Unmarshal of an unterminated string: expected error")
+	} else if _, ok := err.(*json.LimitError); ok {
+		t.Errorf("Unmarshal of an unterminated string: %v, expected syntax error", err)
+	}
+}
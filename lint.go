@@ -0,0 +1,84 @@
+package gjson
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Diagnostic 是 LintMarkdown 发现的一个标题结构问题
+type Diagnostic struct {
+	Line    int    // 标题所在的行号，从 1 开始
+	Rule    string // 问题的类别，例如 "skipped-level"，可以用来过滤
+	Message string // 问题的描述
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", d.Line, d.Message, d.Rule)
+}
+
+// LintMarkdown 检查文档的标题结构，按照行号的顺序返回所有的问题，没有问题时返回空的 slice：
+//   - skipped-level：标题比前一个标题深了不止一级，例如 "#" 之后的 "###"
+//   - multiple-titles：第一个之后的 "#" 标题
+//   - duplicate：同一个父节点下 level 和文本都相同的标题
+//   - empty：没有文本的标题
+//   - numbering：标题文本开头的编号（见 RenumberMarkdown）和 markdownParser 计算的编号不一致，
+//     三种编号写法都可以使用
+func LintMarkdown(r io.Reader) ([]Diagnostic, error) {
+	headings, err := parseHeadings(r)
+	if err != nil {
+		return nil, err
+	}
+	diagnostics := []Diagnostic{}
+	report := func(h mdHeading, rule, format string, args ...any) {
+		diagnostics = append(diagnostics, Diagnostic{Line: h.line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var firstTitle *mdHeading
+	// 以父节点的编号、level 和文本为 key，记录第一次出现的行号
+	seen := map[string]int{}
+	for i, title := range numberHeadings(headings) {
+		h := headings[i]
+		if i > 0 && h.level > headings[i-1].level+1 {
+			report(h, "skipped-level", "heading level %d follows level %d", h.level, headings[i-1].level)
+		}
+		if h.level == 1 {
+			if firstTitle != nil {
+				report(h, "multiple-titles", "another top-level heading, the title is at line %d", firstTitle.line)
+			} else {
+				firstTitle = &headings[i]
+			}
+		}
+
+		text := stripNumber(h.text, strings.Count(title.level, ".")+1, StyleDecimal, StyleRoman, StyleLetters)
+		if text == "" {
+			report(h, "empty", "empty heading")
+		} else {
+			parent := ""
+			if i := strings.LastIndexByte(title.level, '.'); i >= 0 {
+				parent = title.level[:i]
+			}
+			key := fmt.Sprintf("%s/%d/%s", parent, h.level, text)
+			if line, ok := seen[key]; ok {
+				report(h, "duplicate", "duplicate heading %q, first at line %d", text, line)
+			} else {
+				seen[key] = h.line
+			}
+		}
+
+		if number := strings.TrimRight(strings.TrimSpace(h.text[:len(h.text)-len(text)]), "."); number != "" && !matchNumber(number, title.level) {
+			report(h, "numbering", "heading is numbered %s, expected %s", number, title.level)
+		}
+	}
+	return diagnostics, nil
+}
+
+// matchNumber 判断 number 是否是 level 的某一种写法
+func matchNumber(number, level string) bool {
+	for _, style := range []NumberStyle{StyleDecimal, StyleRoman, StyleLetters} {
+		if number == formatNumber(level, style) {
+			return true
+		}
+	}
+	return false
+}
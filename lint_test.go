@@ -0,0 +1,66 @@
+package gjson
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintMarkdown(t *testing.T) {
+	doc := strings.Join([]string{
+		"# 1. Guide",
+		"### 1.1 Details",
+		"## 1.2 Install",
+		"## 1.2 Install",
+		"### A.B.A Linux",
+		"## 1.3 Install ##",
+		"### Linux",
+		"##",
+		"# Appendix",
+		"## 1.9 Usage",
+		"## II.II",
+	}, "\n")
+	expected := []string{
+		"line 2: heading level 3 follows level 1 (skipped-level)",
+		"line 4: duplicate heading \"Install\", first at line 3 (duplicate)",
+		"line 4: heading is numbered 1.2, expected 1.3 (numbering)",
+		"line 5: heading is numbered A.B.A, expected 1.3.1 (numbering)",
+		"line 6: duplicate heading \"Install\", first at line 3 (duplicate)",
+		"line 6: heading is numbered 1.3, expected 1.4 (numbering)",
+		"line 8: empty heading (empty)",
+		"line 9: another top-level heading, the title is at line 1 (multiple-titles)",
+		"line 10: heading is numbered 1.9, expected 2.1 (numbering)",
+		"line 11: empty heading (empty)",
+	}
+	diagnostics, err := LintMarkdown(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("LintMarkdown error %v", err)
+	}
+	var result []string
+	for _, d := range diagnostics {
+		result = append(result, d.String())
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("LintMarkdown =\n%s\nexpected\n%s", strings.Join(result, "\n"), strings.Join(expected, "\n"))
+	}
+
+	renumbered, err := RenumberMarkdown("# Guide\n## Install\n### Linux\n## Usage\n", WithNumberStyle(StyleRoman))
+	if err != nil {
+		t.Fatalf("RenumberMarkdown error %v", err)
+	}
+	if diagnostics, err := LintMarkdown(strings.NewReader(renumbered)); err != nil || len(diagnostics) != 0 {
+		t.Fatalf("LintMarkdown(%q) = %v, %v", renumbered, diagnostics, err)
+	}
+
+	// 不是 RenumberMarkdown 生成的编号是标题文本的一部分
+	changelog := "# Changelog\n## 1.5.3 Bug fixes\n## U.S. History\n## 2.0 Migration\n## 2.0 Migration\n"
+	diagnostics, err = LintMarkdown(strings.NewReader(changelog))
+	expected = []string{"line 5: duplicate heading \"2.0 Migration\", first at line 4 (duplicate)"}
+	result = nil
+	for _, d := range diagnostics {
+		result = append(result, d.String())
+	}
+	if err != nil || !reflect.DeepEqual(result, expected) {
+		t.Fatalf("LintMarkdown(%q) = %q, %v, expected %q", changelog, result, err, expected)
+	}
+}
@@ -1,5 +0,0 @@
-package gjson
-
-func main() {
-	// json.unm
-}
\ No newline at end of file
@@ -1,7 +1,8 @@
 package gjson
 
 import (
-	"fmt"
+	"io"
+	"strconv"
 	"strings"
 )
 
@@ -11,88 +12,57 @@ type mdTitle struct {
 }
 
 type stackState struct {
-	// "#" -> 1, "##" -> 2, "###" -> 3
+	// "#" -> 1, "##" -> 2, 一直到 "######" -> 6
 	level int
 	// 同一个 level 的序列号，1.1 1.2 1.3 的序列号分别为 1、2、3
 	serial int
 }
 
-func markdownParser(input []string) []mdTitle {
+// markdownParser 为每个标题生成层级编号，例如 1、1.1、1.2.1，编号的层数没有限制。
+// 编号反映的是标题的嵌套关系而不是 '#' 的个数：跳过了中间 level 的标题，例如 "#" 之后的 "###"，
+// 编号为前面最近的更高一级标题的直接子节点（1.1 而不是 1.0.1）；
+// 比第一个标题 level 更高的标题和第一个标题编号为同一级。
+// r 是完整的 markdown 文档，标题的识别规则见 parseHeadings
+func markdownParser(r io.Reader) ([]mdTitle, error) {
+	headings, err := parseHeadings(r)
+	if err != nil {
+		return nil, err
+	}
+	return numberHeadings(headings), nil
+}
+
+// numberHeadings 按照 markdownParser 的规则为 headings 编号
+func numberHeadings(headings []mdHeading) []mdTitle {
 	// 用来记录解析 md 的上文，以 stack 的数据结构，包括了当前节点的所有的 parent 节点
 	var stack = []stackState{}
 	var result = []mdTitle{}
 
-	for _, v := range input {
-		fmt.Printf("ele: %s, stack: %+v\n", v, stack)
+	for _, h := range headings {
+		level := h.level
 
-		level, name := parseTitle(v)
-		// case 0. 初始状态
-		if len(stack) == 0 {
-			stack = append(stack, stackState{level: 1, serial: 1})
-			result = append(result, mdTitle{level: "1", name: name})
-			continue
+		// 弹出所有 level 更低的节点。最后弹出的节点和新标题在同一层，新标题的序列号接着它往下数，
+		// 没有弹出节点时 popped.serial 为 0，新标题是第一个子节点
+		var popped stackState
+		for len(stack) > 0 && stack[len(stack)-1].level > level {
+			popped = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
 		}
-		n := len(stack)
-		curFrame := stack[n-1]
-		// case 1. 新的 markdown 与 stack 里面最新的 level 一致
-		if level == curFrame.level {
-			// 先删除当前的 stackFrame
-			stack = stack[0 : n-1]
-			stack = append(stack, stackState{level: level, serial: curFrame.serial + 1})
-			result = append(result, mdTitle{level: stack2Level(stack), name: name})
-		// case 2. 新的 markdown level 大于 stack 里面最新的 level，是更低的节点
-		} else if level > curFrame.level {
-			stack = append(stack, stackState{level: level, serial: 1})
-			result = append(result, mdTitle{level: stack2Level(stack), name: name})
-		// case 3. 新的 markdown level 大于 stack 里面最新的 level，是更高的节点
+		if n := len(stack); n > 0 && stack[n-1].level == level {
+			// 新的 markdown 与 stack 里面最新的 level 一致，是兄弟节点
+			stack[n-1].serial++
 		} else {
-			for {
-				stack = stack[0 : n-1]
-				n = len(stack)
-				curFrame = stack[n-1]
-				if curFrame.level == level {
-					break
-				}
-				continue
-			}
-			// 删除这个同级的 stackFrame, stack 里面只保留 parent 节点
-			stack = stack[0 : n-1]
-			stack = append(stack, stackState{level: level, serial: curFrame.serial + 1})
-			result = append(result, mdTitle{level: stack2Level(stack), name: name})
+			stack = append(stack, stackState{level: level, serial: popped.serial + 1})
 		}
+		result = append(result, mdTitle{level: stack2Level(stack), name: h.text})
 	}
 	return result
 }
 
-func parseTitle(in string) (int, string) {
-	// "### a" -> 3, "a"
-	strArray := strings.Split(in, " ")
-	switch strArray[0] {
-	case "#":
-		return 1, strArray[1]
-	case "##":
-		return 2, strArray[1]
-	case "###":
-		return 3, strArray[1]
-	default:
-		panic("invalid title")
-	}
-}
-
 func stack2Level(stack []stackState) string {
 	// eg. 三个 stack 的 serial 分别为 1 3 2，结果就是 1.3.2
-	serial := []int{}
-	for _, v := range stack {
-		serial = append(serial, v.serial)
-	}
-	switch len(serial) {
-	default:
-		panic("invalid stack")
-	case 1:
-		return fmt.Sprintf("%d", serial[0])
-	case 2:
-		return fmt.Sprintf("%d.%d", serial[0], serial[1])
-	case 3:
-		return fmt.Sprintf("%d.%d.%d", serial[0], serial[1], serial[2])
+	serial := make([]string, len(stack))
+	for i, v := range stack {
+		serial[i] = strconv.Itoa(v.serial)
 	}
+	return strings.Join(serial, ".")
 }
@@ -2,6 +2,7 @@ package gjson
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -14,9 +15,102 @@ func TestMdParser(t *testing.T) {
 		{level: "1.2.1", name: "d"},
 		{level: "2", name: "e"},
 	}
-	result := markdownParser(array)
+	result, err := markdownParser(strings.NewReader(strings.Join(array, "\n")))
+	if err != nil {
+		t.Fatalf("markdownParser error %v", err)
+	}
 	if !reflect.DeepEqual(expected, result) {
 		t.Fatalf("expected: %+v, result: %+v", expected, result)
 	}
 
 }
+
+func TestMdParserDepth(t *testing.T) {
+	tests := []struct {
+		input    []string
+		expected []string
+	}{
+		// 六个 level 都可以使用，编号的层数没有限制
+		{
+			[]string{"# a", "## b", "### c", "#### d", "##### e", "###### f", "###### g", "## h"},
+			[]string{"1", "1.1", "1.1.1", "1.1.1.1", "1.1.1.1.1", "1.1.1.1.1.1", "1.1.1.1.1.2", "1.2"},
+		},
+		// 跳过的 level 不占编号
+		{
+			[]string{"# a", "### b", "### c", "## d", "#### e", "# f"},
+			[]string{"1", "1.1", "1.2", "1.3", "1.3.1", "2"},
+		},
+		// 第一个标题不是 "#"
+		{
+			[]string{"## a", "### b", "## c", "# d", "## e"},
+			[]string{"1", "1.1", "2", "3", "3.1"},
+		},
+	}
+	for _, tt := range tests {
+		result, err := markdownParser(strings.NewReader(strings.Join(tt.input, "\n")))
+		if err != nil {
+			t.Fatalf("markdownParser(%q) error %v", tt.input, err)
+		}
+		var levels []string
+		for _, title := range result {
+			levels = append(levels, title.level)
+		}
+		if !reflect.DeepEqual(levels, tt.expected) {
+			t.Errorf("markdownParser(%q) = %q, expected %q", tt.input, levels, tt.expected)
+		}
+	}
+}
+
+func TestMdParserDocument(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Hello World #",
+		"",
+		"Some text with a # in it.",
+		"#hashtag is not a heading",
+		"```go",
+		"# inside code",
+		"```",
+		"Setext Title",
+		"spanning lines",
+		"===",
+		"",
+		"---",
+		"",
+		"  ## C# ##   ",
+		"~~~~",
+		"## still code",
+		"~~~",
+		"~~~~",
+		"    # indented code",
+		"Sub",
+		"---",
+		"### ###",
+	}, "\r\n")
+	expected := []mdHeading{
+		{level: 1, text: "Hello World", line: 1},
+		{level: 1, text: "Setext Title spanning lines", line: 8},
+		{level: 2, text: "C#", line: 14},
+		{level: 2, text: "Sub", line: 20},
+		{level: 3, text: "", line: 22},
+	}
+	headings, err := parseHeadings(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parseHeadings error %v", err)
+	}
+	if !reflect.DeepEqual(headings, expected) {
+		t.Fatalf("parseHeadings = %+v, expected %+v", headings, expected)
+	}
+
+	result, err := markdownParser(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("markdownParser error %v", err)
+	}
+	var levels []string
+	for _, title := range result {
+		levels = append(levels, title.level+" "+title.name)
+	}
+	expectedLevels := []string{"1 Hello World", "2 Setext Title spanning lines", "2.1 C#", "2.2 Sub", "2.2.1 "}
+	if !reflect.DeepEqual(levels, expectedLevels) {
+		t.Fatalf("markdownParser = %q, expected %q", levels, expectedLevels)
+	}
+}
@@ -0,0 +1,92 @@
+package gjson
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gjson/json"
+)
+
+// Number 保存 JSON 数字的原始文本，和 json.Number 是同一个类型，
+// 所以 json.Marshal 会把它原样输出为数字
+type Number = json.Number
+
+// NumberMode 决定 JSON 数字解析到 any 时使用的 Go 类型
+type NumberMode int
+
+const (
+	// NumberFloat64 把所有数字解析为 float64，这是默认的行为
+	NumberFloat64 NumberMode = iota
+	// NumberString 把数字保存为 Number，不做任何转换
+	NumberString
+	// NumberInt64 把没有小数和指数部分、并且在 int64 范围内的数字解析为 int64，其余为 float64
+	NumberInt64
+	// NumberBig 把整数解析为 *big.Int，其余为 *BigFloat，精度足以保留字面量中的所有数字，
+	// 两者都由 json.Marshal 输出为数字
+	NumberBig
+)
+
+// BigFloat 是 NumberBig 模式下非整数的类型。big.Float 只实现了 MarshalText，
+// json.Marshal 会把它输出为字符串，BigFloat 增加了把它输出为数字的 MarshalJSON
+type BigFloat struct {
+	big.Float
+}
+
+// MarshalJSON 以最短的、能还原出同一个值的形式输出数字，实现 json.Marshaler
+func (f *BigFloat) MarshalJSON() ([]byte, error) {
+	if f.IsInf() {
+		return nil, &json.UnsupportedValueError{Value: reflect.ValueOf(f), Str: f.String()}
+	}
+	return f.Append(nil, 'g', -1), nil
+}
+
+// UseNumber 设置数字解析到 any 时使用的类型，只影响 any 类型的目标，
+// 解析到 int64、float32 等具体类型时仍然按照目标类型转换
+func UseNumber(mode NumberMode) Option {
+	return func(t *parser) {
+		t.numberMode = mode
+	}
+}
+
+// convertNumber 按照 t.numberMode 转换已经通过语法检查的数字字面量
+func (t *parser) convertNumber(lit string) any {
+	switch t.numberMode {
+	case NumberString:
+		return Number(lit)
+	case NumberInt64:
+		if isInteger(lit) {
+			if n, err := strconv.ParseInt(lit, 10, 64); err == nil {
+				return n
+			}
+		}
+	case NumberBig:
+		if isInteger(lit) {
+			n, _ := new(big.Int).SetString(lit, 10)
+			return n
+		}
+		// 每个十进制数字需要不到 4 个二进制位
+		prec := uint(len(lit)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		f := new(BigFloat)
+		f.SetPrec(prec).SetMode(big.ToNearestEven)
+		if _, _, err := f.Parse(lit, 10); err == nil {
+			return f
+		}
+	}
+
+	num, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		// 语法正确但超出了 float64 的范围，和 json 包一样作为类型错误处理
+		t.typeError("number "+lit, reflect.TypeOf(num))
+	}
+	return num
+}
+
+// isInteger 判断数字字面量是否没有小数和指数部分，JSON5 的 Infinity 和 NaN 不是整数
+func isInteger(lit string) bool {
+	return !strings.ContainsAny(lit, ".eEIN")
+}
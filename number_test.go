@@ -0,0 +1,78 @@
+package gjson
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestNumberMode(t *testing.T) {
+	data := `[9007199254740993, -1.5, 1e2, 123456789012345678901234567890, 0.1000000000000000000000000000001]`
+
+	var result any
+	if err := Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	expected := []any{float64(9007199254740993), -1.5, float64(100), 123456789012345678901234567890.0, 0.1}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("NumberFloat64 result %v, expected %v", result, expected)
+	}
+
+	if err := Unmarshal([]byte(data), &result, UseNumber(NumberString)); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	expected = []any{Number("9007199254740993"), Number("-1.5"), Number("1e2"), Number("123456789012345678901234567890"), Number("0.1000000000000000000000000000001")}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("NumberString result %v, expected %v", result, expected)
+	}
+
+	if err := Unmarshal([]byte(data), &result, UseNumber(NumberInt64)); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	expected = []any{int64(9007199254740993), -1.5, float64(100), 123456789012345678901234567890.0, 0.1}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("NumberInt64 result %v, expected %v", result, expected)
+	}
+
+	if err := Unmarshal([]byte(data), &result, UseNumber(NumberBig)); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	items := result.([]any)
+	if n, ok := items[0].(*big.Int); !ok || n.String() != "9007199254740993" {
+		t.Fatalf("NumberBig integer %v", items[0])
+	}
+	if n, ok := items[3].(*big.Int); !ok || n.String() != "123456789012345678901234567890" {
+		t.Fatalf("NumberBig big integer %v", items[3])
+	}
+	if f, ok := items[4].(*BigFloat); !ok || f.Text('g', -1) != "0.1000000000000000000000000000001" {
+		t.Fatalf("NumberBig float %v", items[4])
+	}
+
+	// NumberString 和 NumberBig 解析出的数字都能原样输出
+	for _, mode := range []NumberMode{NumberString, NumberBig} {
+		for _, data := range []string{`{"price":0.10000000000000000000001,"id":123456789012345678901234567890}`, `[-2.5e-30]`} {
+			v, err := ParseValue([]byte(data), UseNumber(mode), OrderedObjects())
+			if err != nil {
+				t.Fatalf("ParseValue: %v", err)
+			}
+			b, err := json.Marshal(v)
+			if err != nil || string(b) != data {
+				t.Errorf("mode %d: Marshal(ParseValue(%s)) = %s, %v", mode, data, b, err)
+			}
+		}
+	}
+
+	// 解析到具体类型时不受 NumberMode 影响
+	var s struct {
+		ID  int64
+		Any any
+	}
+	if err := Unmarshal([]byte(`{"ID": 9007199254740993, "Any": 7}`), &s, UseNumber(NumberInt64)); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s.ID != 9007199254740993 || s.Any != int64(7) {
+		t.Fatalf("typed result %+v", s)
+	}
+}
@@ -0,0 +1,178 @@
+package gjson
+
+import (
+	"bytes"
+	"reflect"
+
+	"gjson/json"
+)
+
+// Member 是 Object 中的一个成员
+type Member struct {
+	Key   string
+	Value any
+}
+
+// Object 按照原始顺序保存 JSON object 的所有成员，重复的 key 也会被保留。
+// 使用 OrderedObjects 选项时，parser 用 Object 代替 map[string]any。
+type Object []Member
+
+// OrderedObjects 让 parser 把 object 解析为 Object，保留 key 的顺序和重复的 key
+func OrderedObjects() Option {
+	return func(t *parser) {
+		t.ordered = true
+	}
+}
+
+// Get 返回 key 对应的值，key 重复时返回最后一个，和 map[string]any 的结果一致
+func (o Object) Get(key string) (any, bool) {
+	for i := len(o) - 1; i >= 0; i-- {
+		if o[i].Key == key {
+			return o[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys 按照原始顺序返回所有的 key
+func (o Object) Keys() []string {
+	keys := make([]string, len(o))
+	for i, m := range o {
+		keys[i] = m.Key
+	}
+	return keys
+}
+
+// Map 把 Object 转换为 map[string]any，嵌套的 Object 保持不变
+func (o Object) Map() map[string]any {
+	m := make(map[string]any, len(o))
+	for _, member := range o {
+		m[member.Key] = member.Value
+	}
+	return m
+}
+
+// MarshalJSON 按照原始顺序输出所有成员，实现 json.Marshaler
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，这样 Object 也可以作为 struct 字段的类型
+func (o *Object) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := Unmarshal(data, &v, OrderedObjects()); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case nil:
+		// 和 json 包的约定一样，null 不做任何修改
+		return nil
+	case Object:
+		*o = v
+		return nil
+	case []any:
+		return &json.UnmarshalTypeError{Value: "array", Type: reflect.TypeOf(o).Elem()}
+	case string:
+		return &json.UnmarshalTypeError{Value: "string", Type: reflect.TypeOf(o).Elem()}
+	case bool:
+		return &json.UnmarshalTypeError{Value: "bool", Type: reflect.TypeOf(o).Elem()}
+	default:
+		return &json.UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(o).Elem()}
+	}
+}
+
+// DuplicateKeys 设置 object 中出现重复 key 时的处理策略，默认是 json.LastKeyWins。
+// 使用 OrderedObjects 时，默认策略会保留所有重复的成员。
+func DuplicateKeys(policy json.DuplicateKeyPolicy) Option {
+	return func(t *parser) {
+		t.duplicateKeys = policy
+	}
+}
+
+// objectKeys 记录一个 object 中已经出现过的 key，只在非默认的重复 key 策略下使用
+type objectKeys map[string]*seenKey
+
+type seenKey struct {
+	// key 第一次出现时在 Object 中的下标
+	index int
+	// 值是否已经被收集为 []any
+	collected bool
+}
+
+// newObjectKeys 在默认策略下返回 nil，这时不需要记录出现过的 key
+func (t *parser) newObjectKeys() objectKeys {
+	if t.duplicateKeys == json.LastKeyWins {
+		return nil
+	}
+	return objectKeys{}
+}
+
+// setKey 按照重复 key 的策略把 key/value 写入 result，offset 是 key 在 t.data 中的位置
+func (t *parser) setKey(result map[string]any, seen objectKeys, key string, value any, offset int) {
+	if seen == nil {
+		result[key] = value
+		return
+	}
+	k, dup := seen[key]
+	if !dup {
+		seen[key] = &seenKey{}
+		result[key] = value
+		return
+	}
+	if value, ok := t.duplicate(k, result[key], key, value, offset); ok {
+		result[key] = value
+	}
+}
+
+// addMember 和 setKey 相同，用于 OrderedObjects 模式
+func (t *parser) addMember(members Object, seen objectKeys, key string, value any, offset int) Object {
+	if seen == nil {
+		return append(members, Member{Key: key, Value: value})
+	}
+	k, dup := seen[key]
+	if !dup {
+		seen[key] = &seenKey{index: len(members)}
+		return append(members, Member{Key: key, Value: value})
+	}
+	if value, ok := t.duplicate(k, members[k.index].Value, key, value, offset); ok {
+		members[k.index].Value = value
+	}
+	return members
+}
+
+// duplicate 决定重复出现的 key 最终对应的值，返回 false 表示丢弃 value、保留 old
+func (t *parser) duplicate(k *seenKey, old any, key string, value any, offset int) (any, bool) {
+	switch t.duplicateKeys {
+	case json.FirstKeyWins:
+		return nil, false
+	case json.RejectDuplicateKeys:
+		t.saveError(&json.DuplicateKeyError{Key: key, Offset: int64(offset)})
+		return nil, false
+	case json.CollectDuplicateKeys:
+		if k.collected {
+			return append(old.([]any), value), true
+		}
+		k.collected = true
+		return []any{old, value}, true
+	}
+	return value, true
+}
@@ -0,0 +1,130 @@
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestOrderedObject(t *testing.T) {
+	data := `{"z": 1, "a": {"y": true, "b": null}, "m": [{"k2": "v", "k1": "w"}], "a": "dup"}`
+
+	var result any
+	if err := Unmarshal([]byte(data), &result, OrderedObjects()); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	expected := Object{
+		{Key: "z", Value: float64(1)},
+		{Key: "a", Value: Object{{Key: "y", Value: true}, {Key: "b", Value: nil}}},
+		{Key: "m", Value: []any{Object{{Key: "k2", Value: "v"}, {Key: "k1", Value: "w"}}}},
+		{Key: "a", Value: "dup"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Unmarshal result %+v, expected %+v", result, expected)
+	}
+
+	obj := result.(Object)
+	if v, ok := obj.Get("a"); !ok || v != "dup" {
+		t.Fatalf("Get(a) = %v, %v", v, ok)
+	}
+	if _, ok := obj.Get("missing"); ok {
+		t.Fatalf("Get(missing) should not exist")
+	}
+	if keys := obj.Keys(); !reflect.DeepEqual(keys, []string{"z", "a", "m", "a"}) {
+		t.Fatalf("Keys() = %v", keys)
+	}
+
+	// json 包按照原始顺序重新输出
+	out, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(out) != `{"z":1,"a":{"y":true,"b":null},"m":[{"k2":"v","k1":"w"}],"a":"dup"}` {
+		t.Fatalf("Marshal result %s", out)
+	}
+
+	// 作为 struct 字段时同样保留顺序，数字的原始文本也可以原样输出
+	var s struct {
+		Config Object
+	}
+	if err := Unmarshal([]byte(`{"Config": {"b": 2, "a": 1}}`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(s.Config.Keys(), []string{"b", "a"}) {
+		t.Fatalf("Config keys %v", s.Config.Keys())
+	}
+	if err := Unmarshal([]byte(`{"Config": [1]}`), &s); err == nil {
+		t.Fatalf("expected error for array into Object")
+	}
+
+	if err := Unmarshal([]byte(`{"n": 12345678901234567890, "f": 1.50}`), &result, OrderedObjects(), UseNumber(NumberString)); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	out, err = json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(out) != `{"n":12345678901234567890,"f":1.50}` {
+		t.Fatalf("Marshal result %s", out)
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	data := `{"a": 1, "b": 2, "a": [3], "a": "4"}`
+	tests := []struct {
+		policy  json.DuplicateKeyPolicy
+		opts    []Option
+		out     any
+		errText string
+	}{
+		{json.LastKeyWins, nil, map[string]any{"a": "4", "b": float64(2)}, ""},
+		{json.FirstKeyWins, nil, map[string]any{"a": float64(1), "b": float64(2)}, ""},
+		{json.RejectDuplicateKeys, nil, map[string]any{"a": float64(1), "b": float64(2)}, `json: duplicate object key "a"`},
+		{json.CollectDuplicateKeys, nil, map[string]any{"a": []any{float64(1), []any{float64(3)}, "4"}, "b": float64(2)}, ""},
+		{json.LastKeyWins, []Option{OrderedObjects()},
+			Object{{"a", float64(1)}, {"b", float64(2)}, {"a", []any{float64(3)}}, {"a", "4"}}, ""},
+		{json.FirstKeyWins, []Option{OrderedObjects()}, Object{{"a", float64(1)}, {"b", float64(2)}}, ""},
+		{json.CollectDuplicateKeys, []Option{OrderedObjects()},
+			Object{{"a", []any{float64(1), []any{float64(3)}, "4"}}, {"b", float64(2)}}, ""},
+	}
+	for _, tt := range tests {
+		var result any
+		err := Unmarshal([]byte(data), &result, append(tt.opts, DuplicateKeys(tt.policy))...)
+		if tt.errText == "" && err != nil || tt.errText != "" && (err == nil || err.Error() != tt.errText) {
+			t.Errorf("policy %d: error %v, expected %q", tt.policy, err, tt.errText)
+		}
+		if !reflect.DeepEqual(result, tt.out) {
+			t.Errorf("policy %d: result %v, expected %v", tt.policy, result, tt.out)
+		}
+	}
+
+	var dupErr *json.DuplicateKeyError
+	var result any
+	err := Unmarshal([]byte(data), &result, DuplicateKeys(json.RejectDuplicateKeys))
+	if !errors.As(err, &dupErr) || dupErr.Key != "a" || dupErr.Offset != 17 {
+		t.Fatalf("expected DuplicateKeyError at offset 17, got %#v", err)
+	}
+
+	// 反射赋值时大小写不敏感地匹配到同一个字段也算重复
+	var s struct {
+		ID  int
+		Any any
+		M   map[string]any
+	}
+	in := `{"ID": 1, "id": 2, "Any": 1, "Any": 2, "M": {"k": 1, "k": 2}}`
+	if err := Unmarshal([]byte(in), &s, DuplicateKeys(json.FirstKeyWins)); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s.ID != 1 || s.Any != float64(1) || !reflect.DeepEqual(s.M, map[string]any{"k": float64(1)}) {
+		t.Fatalf("FirstKeyWins result %+v", s)
+	}
+	s.Any, s.M = nil, nil
+	if err := Unmarshal([]byte(in), &s, DuplicateKeys(json.CollectDuplicateKeys)); !errors.As(err, &dupErr) || dupErr.Key != "id" {
+		t.Fatalf("expected DuplicateKeyError for id, got %v", err)
+	}
+	if !reflect.DeepEqual(s.Any, []any{float64(1), float64(2)}) || !reflect.DeepEqual(s.M, map[string]any{"k": []any{float64(1), float64(2)}}) {
+		t.Fatalf("CollectDuplicateKeys result %+v", s)
+	}
+}
@@ -0,0 +1,20 @@
+package gjson
+
+// Option 用来调整 parser 的解析行为，传给 Unmarshal 等入口函数
+type Option func(*parser)
+
+// CaseSensitive 让 object 的 key 只匹配名字完全相同的 struct 字段，
+// 默认在没有精确匹配时会退回到大小写不敏感的匹配，"ID" 和 "id" 都会写入同一个字段
+func CaseSensitive() Option {
+	return func(t *parser) {
+		t.caseSensitive = true
+	}
+}
+
+// RejectInvalidUTF8 让字符串中的非法 UTF-8 以及落单的 UTF-16 代理项返回 SyntaxError，
+// 默认会把它们替换为 U+FFFD，和 json 包的行为一致
+func RejectInvalidUTF8() Option {
+	return func(t *parser) {
+		t.rejectInvalidUTF8 = true
+	}
+}
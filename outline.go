@@ -0,0 +1,47 @@
+package gjson
+
+import (
+	"io"
+	"strings"
+)
+
+// Outline 是 markdown 文档标题树中的一个节点，可以直接用 json.Marshal 输出
+type Outline struct {
+	// 标题的完整文本
+	Title string `json:"title"`
+	// markdownParser 生成的层级编号，例如 "1.2"
+	Number string `json:"number"`
+	// '#' 的个数，setext 标题是 1 或 2
+	Level int `json:"level"`
+	// 和 GitHub 相同的锚点，不包括开头的 '#'
+	Anchor string `json:"anchor"`
+	// 标题所在的行号，从 1 开始
+	Line     int        `json:"line"`
+	Children []*Outline `json:"children,omitempty"`
+}
+
+// MarkdownOutline 返回文档的标题树，树的结构和 markdownParser 的编号一致：
+// 编号为 1.2.1 的节点是编号为 1.2 的节点的子节点。没有标题时返回空的 slice
+func MarkdownOutline(r io.Reader) ([]*Outline, error) {
+	headings, err := parseHeadings(r)
+	if err != nil {
+		return nil, err
+	}
+	slugs := newSlugger()
+	roots := []*Outline{}
+	// path[i] 是当前节点在第 i 层的祖先，path 的长度是当前节点的层数
+	var path []*Outline
+	for i, title := range numberHeadings(headings) {
+		h := headings[i]
+		node := &Outline{Title: h.text, Number: title.level, Level: h.level, Anchor: slugs.slug(h.text), Line: h.line}
+		depth := strings.Count(title.level, ".")
+		path = append(path[:depth], node)
+		if depth == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := path[depth-1]
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return roots, nil
+}
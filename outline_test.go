@@ -0,0 +1,43 @@
+package gjson
+
+import (
+	"strings"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestMarkdownOutline(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Guide",
+		"## Install",
+		"#### Details",
+		"Usage",
+		"-----",
+		"# Guide",
+	}, "\n")
+	outline, err := MarkdownOutline(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("MarkdownOutline error %v", err)
+	}
+	data, err := json.Marshal(outline)
+	if err != nil {
+		t.Fatalf("json.Marshal error %v", err)
+	}
+	expected := `[{"title":"Guide","number":"1","level":1,"anchor":"guide","line":1,"children":[` +
+		`{"title":"Install","number":"1.1","level":2,"anchor":"install","line":2,"children":[` +
+		`{"title":"Details","number":"1.1.1","level":4,"anchor":"details","line":3}]},` +
+		`{"title":"Usage","number":"1.2","level":2,"anchor":"usage","line":4}]},` +
+		`{"title":"Guide","number":"2","level":1,"anchor":"guide-1","line":6}]`
+	if string(data) != expected {
+		t.Fatalf("json.Marshal(outline) = %s, expected %s", data, expected)
+	}
+
+	outline, err = MarkdownOutline(strings.NewReader("no headings\n"))
+	if err != nil {
+		t.Fatalf("MarkdownOutline error %v", err)
+	}
+	if data, _ := json.Marshal(outline); string(data) != "[]" {
+		t.Fatalf("json.Marshal(empty outline) = %s", data)
+	}
+}
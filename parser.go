@@ -1,21 +1,31 @@
 package gjson
 
 import (
-	"encoding/json"
-	"errors"
+	"reflect"
 	"strconv"
+
+	"gjson/json"
 )
 
-func Unmarshal(data []byte, v any) error {
-	if !json.Valid(data) {
-		return errors.New("invalid json")
-	}
-	p := newParser(data)
-	result := p.parse()
+// Unmarshal 解析 data 并把结果写入 v 指向的值。
+// v 可以是 *any，也可以是 struct、slice、map、基本类型等的指针，规则与 json.Unmarshal 相同。
+// data 不是合法的 JSON 时返回 *SyntaxError，opts 用来调整解析行为。
+func Unmarshal(data []byte, v any, opts ...Option) (err error) {
+	p := newParser(data, opts...)
+	defer p.recover(&err)
+	p.checkSize()
 
-	vptr := v.(*any)
-	*vptr = result
-	return nil
+	// *any 不需要反射，直接构造 map[string]any/[]any
+	if vptr, ok := v.(*any); ok && vptr != nil {
+		*vptr = p.parse()
+		if len(p.errors) > 0 {
+			return p.errors
+		}
+		return p.savedError
+	}
+	// 容错模式只用于构造 any，解析到具体类型时出错就返回
+	p.tolerant = false
+	return p.unmarshal(v)
 }
 
 type parser struct {
@@ -23,17 +33,63 @@ type parser struct {
 	len  int
 	// index 指向当前字符串流的 char
 	index int
+
+	// 反射赋值时遇到的第一个类型错误，以及出错字段所在的 struct 和字段路径
+	savedError  error
+	errorStruct reflect.Type
+	fieldStack  []string
+
+	// 字符串中出现非法 UTF-8 时报错，而不是替换为 U+FFFD
+	rejectInvalidUTF8 bool
+	// key 只精确匹配 struct 字段，不做大小写不敏感的匹配
+	caseSensitive bool
+	// 数字解析到 any 时使用的类型
+	numberMode NumberMode
+	// object 解析为保留顺序的 Object，而不是 map[string]any
+	ordered bool
+	// object 中出现重复 key 时的处理策略
+	duplicateKeys json.DuplicateKeyPolicy
+	// 资源限制，以及当前 array/object 的嵌套深度
+	limits json.Limits
+	depth  int
+	// 不为 nil 时把解析事件交给 handler，而不是构造 map[string]any/[]any
+	handler Handler
+	// 容错模式，以及容错模式下记录的语法错误
+	tolerant bool
+	errors   SyntaxErrors
+	// 接受的 JSON 方言
+	syntax json.Syntax
 }
 
-func newParser(data []byte) *parser {
+func newParser(data []byte, opts ...Option) *parser {
 	// fmt.Println(string(data))
 	p := new(parser)
 	p.data = data
 	p.len = len(data)
+	for _, opt := range opts {
+		opt(p)
+	}
 	return p
 }
 
+// withData 返回一个选项与 t 相同、用来解析 data 的 parser
+func (t *parser) withData(data []byte) *parser {
+	p := *t
+	p.data = data
+	p.len = len(data)
+	p.index = 0
+	p.savedError = nil
+	p.errorStruct = nil
+	p.fieldStack = nil
+	p.depth = 0
+	return &p
+}
+
+// curChar 返回当前的 char，读到结尾时返回 eof
 func (t *parser) curChar() rune {
+	if t.end() {
+		return eof
+	}
 	return rune(t.data[t.index])
 }
 
@@ -49,6 +105,9 @@ func (t *parser) isBlank() bool {
 	switch t.curChar() {
 	case ' ', '\n', '\t', '\r':
 		return true
+	case '\v', '\f':
+		// JSON5 的空白还包括垂直制表符和换页符
+		return t.json5()
 	default:
 		return false
 	}
@@ -57,70 +116,134 @@ func (t *parser) isBlank() bool {
 func (t *parser) tryBool() (any, bool) {
 	switch t.curChar() {
 	case 't':
-		t.index += 4
+		t.passWord("true")
 		return true, true
 	case 'f':
-		t.index += 5
+		t.passWord("false")
 		return false, true
 	default:
 		return nil, false
 	}
 }
 
+// passWord 跳过 true、false、null 这样的字面量，每个字符都必须完全一致
+func (t *parser) passWord(word string) {
+	for i := 0; i < len(word); i++ {
+		if t.curChar() != rune(word[i]) {
+			t.fail(strconv.QuoteRune(rune(word[i])) + " in literal " + word)
+		}
+		t.next()
+	}
+}
+
 func (t *parser) tryString() (any, bool) {
-	var result any
-	switch t.curChar() {
+	switch t.valueChar() {
 	case '"':
-		next := t.index + 1
-		for {
-			if t.data[next] == '"' {
-				result = string(t.data[t.index+1 : next])
-				t.index = next + 1
-				break
-			}
-			// 考虑 escape char
-			if t.data[next] == '\\' && t.data[next+1] == '"' {
-				next += 2
-				continue
-			}
-			next += 1
-		}
-		return result, true
+		return t.parseString(), true
 	default:
-		return result, false
+		return nil, false
 	}
 }
 
 func (t *parser) tryNum() (any, bool) {
-	if (t.curChar() <= '9' && t.curChar() >= '0') || t.curChar() == '-' {
-		// 需要利用终止符寻找数字的结尾
-		next := t.index + 1
-		for {
-			if next == t.len {
-				break
-			}
-			switch t.data[next] {
-			case ',', ' ', '\t', '\n', '\r', ']', '}':
-				// 可以 break 外层的 loop
-				goto FOR
-			default:
-				next += 1
-			}
+	if lit, ok := t.scanNum(); ok {
+		return t.convertNumber(lit), true
+	}
+	return nil, false
+}
+
+// scanNum 按照 RFC 8259 的语法扫描数字，返回数字字面量的原始文本，由调用方决定转换成什么类型。
+// JSON5 的数字由 normalizeNumber 改写为 JSON 的数字
+//
+//	number = [ minus ] int [ frac ] [ exp ]
+//	int    = zero / ( digit1-9 *DIGIT )
+//	frac   = decimal-point 1*DIGIT
+//	exp    = e [ minus / plus ] 1*DIGIT
+func (t *parser) scanNum() (string, bool) {
+	start := t.index
+	if !t.passNum() {
+		return "", false
+	}
+	lit := string(t.data[start:t.index])
+	if t.json5() {
+		lit = normalizeNumber(lit)
+	}
+	return lit, true
+}
+
+// passNum 跳过当前位置的数字，不是数字时返回 false
+func (t *parser) passNum() bool {
+	c := t.curChar()
+	json5 := t.json5()
+	if !isDigit(c) && c != '-' && !(json5 && isNumberStart5(c)) {
+		return false
+	}
+	start := t.index
+	if c == '-' || c == '+' {
+		t.next()
+	}
+	// JSON5 的数字可以省略整数部分或者小数部分，但不能都省略
+	intPart := true
+	switch c = t.curChar(); {
+	case c == '0':
+		// 0 后面不能再跟数字，01 不是合法的数字
+		t.next()
+		if c = t.curChar(); json5 && (c == 'x' || c == 'X') {
+			t.passHex()
+			t.checkNumber(start)
+			return true
+		}
+	case '1' <= c && c <= '9':
+		t.passDigits()
+	case json5 && c == 'I':
+		t.passWord("Infinity")
+		t.checkNumber(start)
+		return true
+	case json5 && c == 'N':
+		t.passWord("NaN")
+		t.checkNumber(start)
+		return true
+	case json5 && c == '.':
+		intPart = false
+	default:
+		t.fail("digit in number")
+	}
+	if t.curChar() == '.' {
+		t.next()
+		if isDigit(t.curChar()) {
+			t.passDigits()
+		} else if !json5 || !intPart {
+			t.fail("digit after decimal point in number")
 		}
-	FOR:
+	}
+	if c = t.curChar(); c == 'e' || c == 'E' {
+		t.next()
+		if c = t.curChar(); c == '+' || c == '-' {
+			t.next()
+		}
+		if !isDigit(t.curChar()) {
+			t.fail("digit in exponent of number")
+		}
+		t.passDigits()
+	}
+	t.checkNumber(start)
+	return true
+}
 
-		num, _ := strconv.ParseFloat(string(t.data[t.index:next]), 64)
-		t.index = next
-		return num, true
-	} else {
-		return nil, false
+func (t *parser) passDigits() {
+	for isDigit(t.curChar()) {
+		t.next()
 	}
 }
 
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
 func (t *parser) tryNull() (any, bool) {
 	switch t.curChar() {
 	case 'n':
-		t.index += 4
+		t.passWord("null")
 		return nil, true
 	default:
 		return nil, false
@@ -142,55 +265,68 @@ func (t *parser) tryPrimitive() (any, bool) {
 	return result, true
 }
 
-func (t *parser) pass(input ...rune) {
-	for {
-		if t.curChar() == ']' || t.curChar() == '}' {
-			return
-		}
-
-		for _, v := range input {
-			if t.curChar() == v {
-				t.next()
-				return
-			}
-		}
+// expect 跳过空白字符后，当前 char 必须是 c
+func (t *parser) expect(c rune, expected string) {
+	t.passBlank()
+	if t.curChar() != c {
+		t.fail(expected)
 	}
+	t.next()
 }
 
-func (t *parser) passComma() {
-	t.passBlank()
-	t.pass(',')
+// passComma 跳过容器元素之间的 ','，遇到容器的结束符 closing 时返回 true
+func (t *parser) passComma(closing rune) bool {
 	t.passBlank()
+	switch t.curChar() {
+	case ',':
+		t.next()
+		t.passBlank()
+		// 宽松语法允许最后一个元素之后的 ','
+		if t.relaxed() && t.curChar() == closing {
+			t.next()
+			return true
+		}
+		return false
+	case closing:
+		t.next()
+		return true
+	default:
+		t.fail("',' or " + strconv.QuoteRune(closing))
+		return false
+	}
 }
 
 func (t *parser) tryArray() (any, bool) {
 	var (
 		result []any
-		ok     bool
 		item   any
 	)
 	switch t.curChar() {
 	case '[':
+		t.enter()
+		defer t.leave()
+		if t.handler != nil {
+			t.emit(t.handler.StartArray())
+		}
 		t.next()
-		for {
-			if t.curChar() == ']' {
-				t.next()
-				break
-			}
-			if t.isBlank() {
-				t.next()
-				continue
-			}
-			if item, ok = t.tryPrimitive(); ok {
-			} else if item, ok = t.tryArray(); ok {
-			} else if item, ok = t.tryObject(); ok {
-			} else {
-				panic("array invalid")
-			}
-			result = append(result, item)
+		t.passBlank()
+		if t.curChar() != ']' {
+			for done := false; !done; {
+				done = t.element(']', func() bool {
+					item = t.parseValue()
+					if t.handler == nil {
+						result = append(result, item)
+					}
 
-			// 跳过数组元素的分隔符
-			t.passComma()
+					// 跳过数组元素的分隔符
+					return t.passComma(']')
+				})
+			}
+		} else {
+			t.next()
+		}
+		if t.handler != nil {
+			t.emit(t.handler.EndArray())
 		}
 		return result, true
 	default:
@@ -199,98 +335,126 @@ func (t *parser) tryArray() (any, bool) {
 }
 
 func (t *parser) parseObjectKey() string {
-	item, _ := t.tryString()
+	if t.isIdentifierKey() {
+		start, end := t.skipIdentifier()
+		return string(t.data[start:end])
+	}
+	item, ok := t.tryString()
+	if !ok {
+		t.fail("object key string")
+	}
 	return item.(string)
 }
 
 func (t *parser) passBlank() {
 	for {
-		if t.isBlank() {
+		if !t.end() && t.isBlank() {
 			t.next()
 			continue
+		} else if t.relaxed() && t.curChar() == '/' {
+			t.passComment()
+			continue
 		} else {
 			return
 		}
 	}
 }
 
-func (t *parser) parseObjectValue() any {
+func (t *parser) parseValue() any {
 	var (
 		item any
 		ok   bool
 	)
 	if item, ok = t.tryPrimitive(); ok {
+		if t.handler != nil {
+			t.emitPrimitive(item)
+		}
 	} else if item, ok = t.tryArray(); ok {
 	} else if item, ok = t.tryObject(); ok {
 	} else {
-		panic("object value invalid")
+		t.fail("value")
 	}
 	return item
 }
 
 func (t *parser) tryObject() (any, bool) {
 	var (
-		result map[string]any = map[string]any{}
-		// item   any
-		key   string
-		value any
+		result  map[string]any
+		members Object
+		seen    objectKeys
+		key     string
+		value   any
 	)
 	switch t.curChar() {
 	case '{':
+		t.enter()
+		defer t.leave()
+		if t.handler != nil {
+			t.emit(t.handler.StartObject())
+		} else if t.ordered {
+			members = Object{}
+		} else {
+			result = map[string]any{}
+		}
 		// 进入了 object 的内部
 		t.next()
-		for {
-			// fmt.Printf("try object [%c]\n", t.curChar())
-			if t.curChar() == '}' {
-				// 离开了 object
-				t.next()
-				break
-			}
-
-			t.passBlank()
-			key = t.parseObjectKey()
-			t.passBlank()
-			t.pass(':')
+		t.passBlank()
+		if t.curChar() == '}' {
+			// 离开了 object
+			t.next()
+			return t.objectResult(result, members), true
+		}
+		if t.handler == nil {
+			seen = t.newObjectKeys()
+		}
+		for done := false; !done; {
+			done = t.element('}', func() bool {
+				offset := t.index
+				key = t.parseObjectKey()
+				t.expect(':', "':' after object key")
+				if t.handler != nil {
+					t.emit(t.handler.Key(key))
+				}
 
-			t.passBlank()
-			value = t.parseObjectValue()
-			result[key] = value
-			t.passBlank()
-			t.passComma()
+				t.passBlank()
+				value = t.parseValue()
+				if t.handler != nil {
+					// Parse 不构造结果
+				} else if t.ordered {
+					members = t.addMember(members, seen, key, value, offset)
+				} else {
+					t.setKey(result, seen, key, value, offset)
+				}
+				return t.passComma('}')
+			})
 		}
-		return result, true
+		return t.objectResult(result, members), true
 	default:
 		return nil, false
 	}
 
 }
 
-func (t *parser) parse() any {
-	var (
-		ok   bool
-		item any
-	)
-	for {
-		// fmt.Printf("parse [%c]\n", t.curChar())
-		if t.end() {
-			break
-		}
-		if t.isBlank() {
-			t.next()
-			continue
-		}
-
-		if item, ok = t.tryPrimitive(); ok {
-			return item
-		} else if item, ok = t.tryArray(); ok {
-			return item
-		} else if item, ok = t.tryObject(); ok {
-			return item
-		} else {
-			panic("invalid")
-		}
-
+// objectResult 根据 t.ordered 选择 tryObject 返回的类型，Parse 中在这里产生 EndObject 事件
+func (t *parser) objectResult(result map[string]any, members Object) any {
+	if t.handler != nil {
+		t.emit(t.handler.EndObject())
+		return nil
 	}
+	if t.ordered {
+		return members
+	}
+	return result
+}
 
-	return nil
+func (t *parser) parse() (item any) {
+	t.passBlank()
+	t.catch(func() {
+		item = t.parseValue()
+		t.passBlank()
+		if !t.end() {
+			t.fail("end of input")
+		}
+	})
+	return item
 }
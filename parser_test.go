@@ -39,7 +39,7 @@ func TestParser(t *testing.T) {
 
 	// with escape char
 	data = ` "Hello \" world!"	`
-	expected = `Hello \" world!`
+	expected = `Hello " world!`
 	if err := Unmarshal([]byte(data), &result); err != nil {
 		t.Fatalf("str err %v", err)
 	}
@@ -280,3 +280,159 @@ func TestRFCExample(t *testing.T) {
 		t.Fatalf("array Unmarshal err, result: %+v\n, expected: %+v", arrayResult, arrayExpected)
 	}
 }
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		data     string
+		offset   int64
+		line     int
+		column   int
+		char     rune
+		expected string
+	}{
+		{``, 0, 1, 1, eof, "value"},
+		{`   `, 3, 1, 4, eof, "value"},
+		{`[1, 2`, 5, 1, 6, eof, "',' or ']'"},
+		{`[1 2]`, 3, 1, 4, '2', "',' or ']'"},
+		{`[1,]`, 3, 1, 4, ']', "value"},
+		{`{"a" 1}`, 5, 1, 6, '1', "':' after object key"},
+		{`{"a": 1,}`, 8, 1, 9, '}', "object key string"},
+		{`{a: 1}`, 1, 1, 2, 'a', "object key string"},
+		{"{\n\t\"a\": 1\n\t\"b\": 2\n}", 11, 3, 2, '"', "',' or '}'"},
+		{`"abc`, 4, 1, 5, eof, "closing quote of string"},
+		{`["你好", x]`, 11, 1, 8, 'x', "value"},
+		{`true false`, 5, 1, 6, 'f', "end of input"},
+		{`nul`, 3, 1, 4, eof, "'l' in literal null"},
+	}
+	for _, tt := range tests {
+		var result any
+		err := Unmarshal([]byte(tt.data), &result)
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Unmarshal(%q) error %v, expected *SyntaxError", tt.data, err)
+			continue
+		}
+		if se.Offset != tt.offset || se.Line != tt.line || se.Column != tt.column || se.Char != tt.char || se.Expected != tt.expected {
+			t.Errorf("Unmarshal(%q) error %+v, expected offset %d line %d column %d char %q expected %q",
+				tt.data, se, tt.offset, tt.line, tt.column, tt.char, tt.expected)
+		}
+	}
+
+	// 反射赋值时同样返回 SyntaxError
+	var s struct{ A []int }
+	if err := Unmarshal([]byte(`{"A": [1, 2}`), &s); err == nil {
+		t.Fatalf("expected error")
+	} else if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %T %v", err, err)
+	}
+}
+
+func TestParserStringEscape(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`"a\nb"`, "a\nb"},
+		{`"\"\\\/\b\f\n\r\t"`, "\"\\/\b\f\n\r\t"},
+		{`"\u4f60\u597D"`, "你好"},
+		{`"\ud83d\ude00"`, "\U0001F600"},
+		{`"\ud83d"`, "\uFFFD"},
+		{`"\ud83d\u0041"`, "\uFFFDA"},
+		{`"\ude00\ud83d\ude00"`, "\uFFFD\U0001F600"},
+		{"\"a\xffb\"", "a\uFFFDb"},
+		{`"tail\\"`, `tail\`},
+	}
+	for _, tt := range tests {
+		var result any
+		if err := Unmarshal([]byte(tt.data), &result); err != nil {
+			t.Errorf("Unmarshal(%q): %v", tt.data, err)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("Unmarshal(%q) = %q, expected %q", tt.data, result, tt.expected)
+		}
+	}
+
+	errTests := []struct {
+		data     string
+		offset   int64
+		expected string
+		opts     []Option
+	}{
+		{`"\x41"`, 2, "valid escape character", nil},
+		{`"\u12g4"`, 5, "hexadecimal digit in \\u escape", nil},
+		{`"\u12`, 5, "hexadecimal digit in \\u escape", nil},
+		{"\"a\tb\"", 2, "escaped control character in string", nil},
+		{"\"a\xffb\"", 2, "valid UTF-8 in string", []Option{RejectInvalidUTF8()}},
+		{`"ab\ud83d"`, 3, "valid surrogate pair", []Option{RejectInvalidUTF8()}},
+	}
+	for _, tt := range errTests {
+		var result any
+		err := Unmarshal([]byte(tt.data), &result, tt.opts...)
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Unmarshal(%q) error %v, expected *SyntaxError", tt.data, err)
+			continue
+		}
+		if se.Offset != tt.offset || se.Expected != tt.expected {
+			t.Errorf("Unmarshal(%q) error %+v, expected offset %d expected %q", tt.data, se, tt.offset, tt.expected)
+		}
+	}
+}
+
+func TestParserStrictLiteral(t *testing.T) {
+	valid := map[string]any{
+		`0`:         float64(0),
+		`-0`:        float64(0),
+		`1.5e3`:     float64(1500),
+		`-12.25E-2`: float64(-0.1225),
+		`2e+2`:      float64(200),
+		` [0,-1] `:  []any{float64(0), float64(-1)},
+		`true`:      true,
+		`[null]`:    []any{nil},
+	}
+	for data, expected := range valid {
+		var result any
+		if err := Unmarshal([]byte(data), &result); err != nil {
+			t.Errorf("Unmarshal(%q): %v", data, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Unmarshal(%q) = %v, expected %v", data, result, expected)
+		}
+	}
+
+	invalid := []struct {
+		data     string
+		offset   int64
+		expected string
+	}{
+		{`tx`, 1, "'r' in literal true"},
+		{`nulx`, 3, "'l' in literal null"},
+		{`fals`, 4, "'e' in literal false"},
+		{`truex`, 4, "end of input"},
+		{`01`, 1, "end of input"},
+		{`[01]`, 2, "',' or ']'"},
+		{`1.`, 2, "digit after decimal point in number"},
+		{`.5`, 0, "value"},
+		{`--3`, 1, "digit in number"},
+		{`-`, 1, "digit in number"},
+		{`1e`, 2, "digit in exponent of number"},
+		{`1e+`, 3, "digit in exponent of number"},
+		{`+1`, 0, "value"},
+		{`0x10`, 1, "end of input"},
+		{`-Inf`, 1, "digit in number"},
+	}
+	for _, tt := range invalid {
+		var result any
+		err := Unmarshal([]byte(tt.data), &result)
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Unmarshal(%q) error %v, expected *SyntaxError", tt.data, err)
+			continue
+		}
+		if se.Offset != tt.offset || se.Expected != tt.expected {
+			t.Errorf("Unmarshal(%q) error %+v, expected offset %d expected %q", tt.data, se, tt.offset, tt.expected)
+		}
+	}
+}
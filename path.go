@@ -0,0 +1,332 @@
+package gjson
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gjson/json"
+)
+
+// Query 按照 path 从 data 中取出一个值，只解析 path 经过的部分和最终选中的值，不会构造整个文档。
+//
+// path 由 '.' 分隔的多段组成，每一段的含义：
+//
+//	name      object 中的 key，如果当前值是 array，name 为数字时表示下标，例如 items.0.price
+//	*, ?      通配符，* 匹配任意多个字符，? 匹配一个字符，选中第一个匹配的 key 或下标
+//	#         作为最后一段时返回 array 的长度，否则对 array 的每个元素查询剩下的 path，
+//	          结果组成一个 array，例如 users.#.name
+//	\         转义下一个字符，例如 a\.b 表示 key "a.b"
+//
+// 没有匹配的值时返回不存在的 Value，不是错误。
+// 只检查扫描过的部分是否为合法的 JSON：为了查找重复的 key，path 经过的 object 会被完整地扫描
+// （json.FirstKeyWins 除外），其他选中值之后的内容不会被检查。
+// object 中有重复的 key 时和 Unmarshal 一样按照 DuplicateKeys 设置的策略处理：默认选中最后一个，
+// json.FirstKeyWins 选中第一个，json.RejectDuplicateKeys 返回第一个值和 *json.DuplicateKeyError，
+// json.CollectDuplicateKeys 在 path 的最后一段返回所有值组成的 array，中间的段选中最后一个。
+// 只检查被选中的 key 是否重复，通配符选中第一个匹配的 key，之后同名的 key 是它的重复。
+func Query(data []byte, path string, opts ...Option) (v Value, err error) {
+	p := newParser(data, opts...)
+	p.tolerant = false
+	defer p.recover(&err)
+	p.checkSize()
+
+	p.passBlank()
+	result, ok := p.query(parsePath(path), false)
+	if !ok {
+		return Value{}, p.savedError
+	}
+	return ValueOf(result), p.savedError
+}
+
+// pathComponent 是 path 中的一段
+type pathComponent struct {
+	// key 是去掉转义之后的 key；wild 为 true 时是保留转义的通配符模式
+	key   string
+	wild  bool
+	count bool
+	// key 是非负整数时作为 array 的下标，否则为 -1
+	index int
+}
+
+// parsePath 把 path 拆分为 pathComponent，空的 path 表示整个文档
+func parsePath(path string) []pathComponent {
+	if path == "" {
+		return nil
+	}
+	var components []pathComponent
+	var key, pattern strings.Builder
+	wild := false
+	for i := 0; ; i++ {
+		if i == len(path) || path[i] == '.' {
+			c := pathComponent{key: key.String(), wild: wild, index: -1}
+			if wild {
+				c.key = pattern.String()
+			} else if pattern.String() == "#" {
+				c.count = true
+			}
+			if n, err := strconv.Atoi(c.key); err == nil && n >= 0 && !wild {
+				c.index = n
+			}
+			components = append(components, c)
+			if i == len(path) {
+				return components
+			}
+			key.Reset()
+			pattern.Reset()
+			wild = false
+			continue
+		}
+		switch c := path[i]; {
+		case c == '\\' && i+1 < len(path):
+			i++
+			key.WriteByte(path[i])
+			pattern.WriteByte('\\')
+			pattern.WriteByte(path[i])
+		case c == '*' || c == '?':
+			wild = true
+			key.WriteByte(c)
+			pattern.WriteByte(c)
+		default:
+			key.WriteByte(c)
+			pattern.WriteByte(c)
+		}
+	}
+}
+
+// match 检查 key 是否和这一段匹配
+func (c pathComponent) match(key string) bool {
+	if c.wild {
+		return wildcardMatch(c.key, key)
+	}
+	return !c.count && key == c.key
+}
+
+// matchIndex 检查 array 的下标 i 是否和这一段匹配
+func (c pathComponent) matchIndex(i int) bool {
+	if c.wild {
+		return wildcardMatch(c.key, strconv.Itoa(i))
+	}
+	return c.index == i
+}
+
+// wildcardMatch 检查 s 是否匹配 pattern，pattern 中的 '\' 转义下一个字符
+func wildcardMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			pattern = pattern[1:]
+			for i := 0; i <= len(s); i++ {
+				if wildcardMatch(pattern, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			_, size := utf8.DecodeRuneInString(s)
+			pattern, s = pattern[1:], s[size:]
+		case '\\':
+			pattern = pattern[1:]
+			fallthrough
+		default:
+			if len(s) == 0 || len(pattern) == 0 || s[0] != pattern[0] {
+				return false
+			}
+			pattern, s = pattern[1:], s[1:]
+		}
+	}
+	return len(s) == 0
+}
+
+// query 在当前位置的值中查询 path，返回选中的值。
+// consume 为 true 时总是跳过整个当前值，否则选中之后立即返回，不再扫描剩下的内容。
+func (t *parser) query(path []pathComponent, consume bool) (any, bool) {
+	if len(path) == 0 {
+		return t.parseValue(), true
+	}
+	switch t.curChar() {
+	case '{':
+		return t.queryObject(path[0], path[1:], consume)
+	case '[':
+		return t.queryArray(path[0], path[1:], consume)
+	}
+	// 不是容器时没有匹配的值，但仍然要检查这个值本身，空的输入或者不完整的值是语法错误
+	t.skipValue()
+	return nil, false
+}
+
+func (t *parser) queryObject(c pathComponent, rest []pathComponent, consume bool) (any, bool) {
+	t.enter()
+	defer t.leave()
+	t.next()
+	t.passBlank()
+	if t.curChar() == '}' {
+		t.next()
+		return nil, false
+	}
+	// selected 是第一个匹配的 key，它再次出现时按照 t.duplicateKeys 决定结果
+	var (
+		result    any
+		found, ok bool
+		selected  string
+		collected []any
+	)
+	for {
+		offset := t.index
+		key, match := t.matchKey(c)
+		t.passBlank()
+		switch {
+		case !match || found && key != selected:
+			t.skipValue()
+		case t.duplicateKeys == json.FirstKeyWins:
+			result, ok := t.query(rest, consume)
+			if consume {
+				t.skipRest('}')
+			}
+			return result, ok
+		case !found:
+			found, selected = true, key
+			result, ok = t.query(rest, true)
+			if t.duplicateKeys == json.CollectDuplicateKeys && len(rest) == 0 {
+				collected = []any{result}
+			}
+		case t.duplicateKeys == json.RejectDuplicateKeys:
+			t.saveError(&json.DuplicateKeyError{Key: key, Offset: int64(offset)})
+			t.skipValue()
+		default:
+			result, ok = t.query(rest, true)
+			if collected != nil {
+				collected = append(collected, result)
+				result = collected
+			}
+		}
+		if t.passComma('}') {
+			return result, ok
+		}
+	}
+}
+
+// matchKey 跳过 object 的 key 和之后的 ':'，返回 key 以及它是否和 c 匹配
+func (t *parser) matchKey(c pathComponent) (string, bool) {
+	if t.isIdentifierKey() {
+		start, end := t.skipIdentifier()
+		t.expect(':', "':' after object key")
+		key := string(t.data[start:end])
+		return key, c.match(key)
+	}
+	if t.valueChar() != '"' {
+		t.fail("object key string")
+	}
+	quote := t.index
+	start, end, decode := t.skipString()
+	var key string
+	if decode {
+		next := t.index
+		t.index = quote
+		key = t.parseString()
+		t.index = next
+	} else {
+		key = string(t.data[start:end])
+	}
+	t.expect(':', "':' after object key")
+	return key, c.match(key)
+}
+
+func (t *parser) queryArray(c pathComponent, rest []pathComponent, consume bool) (any, bool) {
+	t.enter()
+	defer t.leave()
+	t.next()
+	t.passBlank()
+	empty := t.curChar() == ']'
+	if empty {
+		t.next()
+	}
+	switch {
+	case c.count && len(rest) == 0:
+		n := 0
+		for !empty {
+			t.skipValue()
+			n++
+			empty = t.passComma(']')
+		}
+		return float64(n), true
+	case c.count:
+		results := []any{}
+		for !empty {
+			if result, ok := t.query(rest, true); ok {
+				results = append(results, result)
+			}
+			empty = t.passComma(']')
+		}
+		return results, true
+	}
+	for i := 0; !empty; i++ {
+		if c.matchIndex(i) {
+			result, ok := t.query(rest, consume)
+			if consume {
+				t.skipRest(']')
+			}
+			return result, ok
+		}
+		t.skipValue()
+		empty = t.passComma(']')
+	}
+	return nil, false
+}
+
+// skipValue 跳过当前位置的值并检查语法，不构造解析结果
+func (t *parser) skipValue() {
+	switch c := t.valueChar(); c {
+	case '"':
+		t.skipString()
+	case '[', '{':
+		t.enter()
+		defer t.leave()
+		closing := rune(']')
+		if c == '{' {
+			closing = '}'
+		}
+		t.next()
+		t.passBlank()
+		if t.curChar() == closing {
+			t.next()
+			return
+		}
+		t.skipElement(closing)
+		t.skipRest(closing)
+	case 't', 'f':
+		t.tryBool()
+	case 'n':
+		t.tryNull()
+	default:
+		if !t.passNum() {
+			t.fail("value")
+		}
+	}
+}
+
+// skipElement 跳过容器中的一个元素，object 的元素包括 key 和 ':'
+func (t *parser) skipElement(closing rune) {
+	if closing == '}' {
+		if t.isIdentifierKey() {
+			t.skipIdentifier()
+		} else if t.valueChar() != '"' {
+			t.fail("object key string")
+		} else {
+			t.skipString()
+		}
+		t.expect(':', "':' after object key")
+		t.passBlank()
+	}
+	t.skipValue()
+}
+
+// skipRest 在容器的一个元素之后调用，跳过剩下的所有元素和结束符 closing
+func (t *parser) skipRest(closing rune) {
+	for !t.passComma(closing) {
+		t.skipElement(closing)
+	}
+}
@@ -0,0 +1,145 @@
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestQuery(t *testing.T) {
+	data := []byte(`{
+		"users": [
+			{"name": "alice", "age": 30, "tags": ["admin"]},
+			{"name": "bob", "age": 25},
+			{"nick": "carol"}
+		],
+		"items": [{"price": 9.5}, {"price": 12}],
+		"a.b": {"c": 1},
+		"name": "escaped",
+		"empty": [],
+		"meta": {"version": "1.0", "v2": null}
+	}`)
+	tests := []struct {
+		path     string
+		expected any
+		exists   bool
+	}{
+		{"users.0.name", "alice", true},
+		{"users.1.age", float64(25), true},
+		{"items.1.price", float64(12), true},
+		{"users.#", float64(3), true},
+		{"empty.#", float64(0), true},
+		{"users.#.name", []any{"alice", "bob"}, true},
+		{"users.#.tags.0", []any{"admin"}, true},
+		{"empty.#.name", []any{}, true},
+		{"users.0.tags", []any{"admin"}, true},
+		{"meta.v*", "1.0", true},
+		{"meta.v?", nil, true},
+		{"m*.version", "1.0", true},
+		{"users.*.name", "alice", true},
+		{`a\.b.c`, float64(1), true},
+		{"name", "escaped", true},
+		{"", nil, false},
+		{"users.3.name", nil, false},
+		{"users.2.name", nil, false},
+		{"users.name", nil, false},
+		{"meta.version.x", nil, false},
+		{"missing", nil, false},
+		{"meta.#", nil, false},
+	}
+	for _, tt := range tests {
+		v, err := Query(data, tt.path)
+		if err != nil {
+			t.Errorf("Query(%q): %v", tt.path, err)
+			continue
+		}
+		if tt.path == "" {
+			// 空的 path 选中整个文档
+			if v.Kind() != KindObject {
+				t.Errorf("Query(\"\") kind %v", v.Kind())
+			}
+			continue
+		}
+		if v.Exists() != tt.exists || !reflect.DeepEqual(v.Interface(), tt.expected) {
+			t.Errorf("Query(%q) = %#v (exists %v), expected %#v", tt.path, v.Interface(), v.Exists(), tt.expected)
+		}
+	}
+}
+
+func TestQuerySyntaxError(t *testing.T) {
+	// 默认要扫描整个 object 查找重复的 key，json.FirstKeyWins 时选中的值之后的内容不会被检查
+	v, err := Query([]byte(`{"a": 1, "b": ]`), "a", DuplicateKeys(json.FirstKeyWins))
+	if err != nil || v.Int() != 1 {
+		t.Fatalf("Query: %v, %v", v.Interface(), err)
+	}
+	if _, err := Query([]byte(`{"a": 1, "b": ]`), "a"); err == nil {
+		t.Fatalf("Query: expected syntax error after the selected value")
+	}
+	v, err = Query([]byte(`[1, ]`), "0")
+	if err != nil || v.Int() != 1 {
+		t.Fatalf("Query: %v, %v", v.Interface(), err)
+	}
+
+	tests := []struct {
+		data   string
+		path   string
+		offset int64
+	}{
+		{`{"a": [1, 2,], "b": 1}`, "b", 12},
+		{`{"a" 1}`, "a", 5},
+		{`[{"a": tru}]`, "#.a", 10},
+		{`{"a": "x\q", "b": 1}`, "b", 9},
+		// 没有匹配的值之前也要检查当前值本身
+		{``, "a", 0},
+		{`nul`, "a", 3},
+		{`[1, x]`, "a", 4},
+	}
+	for _, tt := range tests {
+		_, err := Query([]byte(tt.data), tt.path)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Offset != tt.offset {
+			t.Errorf("Query(%q, %q) error %v, expected syntax error at offset %d", tt.data, tt.path, err, tt.offset)
+		}
+	}
+}
+
+func TestQueryDuplicateKeys(t *testing.T) {
+	data := []byte(`{"role": "user", "a": {"x": 1}, "role": "admin", "a": {"y": 2}, "role": "root"}`)
+	tests := []struct {
+		policy   json.DuplicateKeyPolicy
+		path     string
+		expected any
+		exists   bool
+		err      error
+	}{
+		{json.LastKeyWins, "role", "root", true, nil},
+		{json.LastKeyWins, "a.y", float64(2), true, nil},
+		{json.LastKeyWins, "a.x", nil, false, nil},
+		{json.LastKeyWins, "r*", "root", true, nil},
+		{json.FirstKeyWins, "role", "user", true, nil},
+		{json.FirstKeyWins, "a.x", float64(1), true, nil},
+		{json.RejectDuplicateKeys, "role", "user", true, &json.DuplicateKeyError{Key: "role", Offset: 32}},
+		{json.RejectDuplicateKeys, "a.x", float64(1), true, &json.DuplicateKeyError{Key: "a", Offset: 49}},
+		{json.CollectDuplicateKeys, "role", []any{"user", "admin", "root"}, true, nil},
+		{json.CollectDuplicateKeys, "a.y", float64(2), true, nil},
+	}
+	for _, tt := range tests {
+		v, err := Query(data, tt.path, DuplicateKeys(tt.policy))
+		if !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("Query(%q, %v) error %v, expected %v", tt.path, tt.policy, err, tt.err)
+		}
+		if v.Exists() != tt.exists || !reflect.DeepEqual(v.Interface(), tt.expected) {
+			t.Errorf("Query(%q, %v) = %#v, expected %#v", tt.path, tt.policy, v.Interface(), tt.expected)
+		}
+	}
+
+	// Query 和 Unmarshal、Value.Get 对同一个文档给出相同的结果
+	var s struct{ Role string }
+	value, _ := ParseValue(data)
+	q, _ := Query(data, "role")
+	if err := Unmarshal(data, &s); err != nil || s.Role != q.String() || value.Get("role").String() != q.String() {
+		t.Fatalf("Unmarshal = %q, Value.Get = %q, Query = %q", s.Role, value.Get("role").String(), q.String())
+	}
+}
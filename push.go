@@ -0,0 +1,418 @@
+package gjson
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"gjson/json"
+)
+
+// ErrNeedMore 表示 PushParser 还没有解析完成的值，需要继续 Push 数据
+var ErrNeedMore = errors.New("gjson: need more data")
+
+// PushParser 是增量的 parser，适合在网络读循环中使用：每读到一段数据就调用 Push，
+// 解析状态保存在 PushParser 中，每解析完一个顶层的值就可以通过 Next 取出。
+// 它只缓存还没有结束的字符串、数字等字面量，不需要把整个文档放在内存中。
+// 输入可以包含多个以空白分隔的顶层值，例如 NDJSON。
+type PushParser struct {
+	// p 保存 Unmarshal 的选项，字面量交给用相同选项创建的 parser 解析
+	p *parser
+
+	state int
+	stack []pushFrame
+
+	// 还没有结束的字面量
+	lit       []byte
+	litKind   byte
+	litEscape bool
+	litKey    bool
+	litPos    position
+
+	// 已经解析完成、还没有被 Next 取走的顶层值
+	values []any
+
+	// pos 是下一个字节在整个输入中的位置，valueStart 是当前顶层值开始的位置
+	pos        position
+	valueStart int64
+	inValue    bool
+
+	closed bool
+	err    error
+}
+
+// position 记录字节在整个输入中的偏移、行号和列号，用来生成 SyntaxError
+type position struct {
+	offset int64
+	line   int
+	column int
+}
+
+// PushParser 在字节之间所处的状态
+const (
+	pushValue        = iota // 期望一个值
+	pushValueOrClose        // '[' 之后，期望一个值或者 ']'
+	pushKeyOrClose          // '{' 之后，期望 key 或者 '}'
+	pushKey                 // object 中 ',' 之后，期望 key
+	pushColon               // key 之后，期望 ':'
+	pushCommaOrClose        // 容器中的值之后，期望 ',' 或者结束符
+)
+
+// pushFrame 是一个还没有结束的 array 或 object
+type pushFrame struct {
+	isObject bool
+	array    []any
+	object   map[string]any
+	members  Object
+	seen     objectKeys
+	key      string
+	keyPos   int64
+}
+
+// NewPushParser 创建一个 PushParser，opts 和 Unmarshal 的选项相同，
+// 其中 Limits 的 MaxBytes 限制的是单个顶层值的字节数
+func NewPushParser(opts ...Option) *PushParser {
+	p := &PushParser{
+		p:   newParser(nil, opts...),
+		pos: position{line: 1, column: 1},
+	}
+	// 逐字节的状态机只认识严格语法
+	p.p.syntax = json.StrictSyntax
+	return p
+}
+
+// Push 解析下一段输入。返回错误之后 PushParser 不能再继续使用。
+// Push 不会保留 chunk，调用返回后可以复用它。
+func (p *PushParser) Push(chunk []byte) (err error) {
+	if p.err != nil {
+		return p.err
+	}
+	if p.closed {
+		return errors.New("gjson: Push after Close")
+	}
+	defer p.finish(&err)
+	defer p.p.recover(&err)
+
+	for i := 0; i < len(chunk); i++ {
+		p.step(chunk[i], chunk[i:])
+		p.advance(chunk[i])
+	}
+	return nil
+}
+
+// Close 表示输入已经结束，如果最后一个值还没有完整地出现，返回 SyntaxError
+func (p *PushParser) Close() (err error) {
+	if p.err != nil || p.closed {
+		return p.err
+	}
+	defer p.finish(&err)
+	defer p.p.recover(&err)
+
+	p.closed = true
+	if p.lit != nil {
+		if p.litKind == '"' {
+			p.failAt(p.pos, eof, "closing quote of string")
+		}
+		p.endLiteral(eof)
+	}
+	if len(p.stack) > 0 || p.state != pushValue {
+		p.failAt(p.pos, eof, p.expected())
+	}
+	return nil
+}
+
+// Next 返回下一个解析完成的顶层值。
+// 还没有完成的值时返回 ErrNeedMore，Close 之后所有的值都被取走时返回 io.EOF。
+func (p *PushParser) Next() (any, error) {
+	if len(p.values) > 0 {
+		v := p.values[0]
+		p.values = p.values[1:]
+		return v, nil
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.closed {
+		return nil, io.EOF
+	}
+	return nil, ErrNeedMore
+}
+
+// finish 在 Push、Close 返回前调用，没有语法错误时返回保存的类型错误等，
+// 出错之后的调用都返回同一个错误
+func (p *PushParser) finish(err *error) {
+	if *err == nil {
+		*err = p.p.savedError
+	}
+	p.err = *err
+}
+
+// advance 在处理完一个字节后更新位置，列号按 UTF-8 字符计数
+func (p *PushParser) advance(c byte) {
+	p.pos.offset++
+	switch {
+	case c == '\n':
+		p.pos.line++
+		p.pos.column = 1
+	case c&0xC0 != 0x80:
+		p.pos.column++
+	}
+	if p.inValue {
+		if max := p.p.limits.MaxBytes; max > 0 && p.pos.offset-p.valueStart > max {
+			p.p.limitError("bytes", max, int(p.valueStart+max+1))
+		}
+	}
+}
+
+// step 处理一个字节，rest 是从这个字节开始的剩余输入，只用来在出错时解码完整的字符
+func (p *PushParser) step(c byte, rest []byte) {
+	if p.lit != nil {
+		if p.literalByte(c) {
+			return
+		}
+		// 数字和 true/false/null 需要遇到下一个字符才知道已经结束，这个字符还要继续处理
+		r, _ := utf8.DecodeRune(rest)
+		p.endLiteral(r)
+	}
+
+	switch c {
+	case ' ', '\t', '\n', '\r':
+		return
+	}
+
+	switch p.state {
+	case pushValueOrClose:
+		if c == ']' {
+			p.closeFrame()
+			return
+		}
+		p.beginValue(c, rest)
+	case pushValue:
+		p.beginValue(c, rest)
+	case pushKeyOrClose, pushKey:
+		if c == '}' && p.state == pushKeyOrClose {
+			p.closeFrame()
+			return
+		}
+		if c != '"' {
+			p.fail(rest, "object key string")
+		}
+		p.beginLiteral(c, true)
+	case pushColon:
+		if c != ':' {
+			p.fail(rest, "':' after object key")
+		}
+		p.state = pushValue
+	case pushCommaOrClose:
+		top := &p.stack[len(p.stack)-1]
+		switch {
+		case c == ',' && top.isObject:
+			p.state = pushKey
+		case c == ',':
+			p.state = pushValue
+		case c == '}' && top.isObject, c == ']' && !top.isObject:
+			p.closeFrame()
+		default:
+			p.fail(rest, p.expected())
+		}
+	}
+}
+
+// beginValue 处理一个值的第一个字节
+func (p *PushParser) beginValue(c byte, rest []byte) {
+	if !p.inValue {
+		p.inValue = true
+		p.valueStart = p.pos.offset
+	}
+	switch {
+	case c == '[' || c == '{':
+		max := p.p.limits.MaxDepth
+		if max <= 0 {
+			max = maxNestingDepth
+		}
+		if len(p.stack) >= max {
+			p.p.limitError("depth", int64(max), int(p.pos.offset)+1)
+		}
+		frame := pushFrame{isObject: c == '{'}
+		if c == '[' {
+			p.state = pushValueOrClose
+		} else {
+			p.state = pushKeyOrClose
+			if p.p.ordered {
+				frame.members = Object{}
+			} else {
+				frame.object = map[string]any{}
+			}
+			frame.seen = p.p.newObjectKeys()
+		}
+		p.stack = append(p.stack, frame)
+	case c == '"', c == 't', c == 'f', c == 'n', c == '-', '0' <= c && c <= '9':
+		p.beginLiteral(c, false)
+	default:
+		p.fail(rest, "value")
+	}
+}
+
+func (p *PushParser) beginLiteral(c byte, key bool) {
+	p.lit = append(p.lit[:0], c)
+	p.litKind = c
+	p.litEscape = false
+	p.litKey = key
+	p.litPos = p.pos
+}
+
+// literalByte 把 c 追加到当前的字面量，返回 false 表示字面量在 c 之前已经结束
+func (p *PushParser) literalByte(c byte) bool {
+	switch p.litKind {
+	case '"':
+		p.lit = append(p.lit, c)
+		switch {
+		case p.litEscape:
+			p.litEscape = false
+		case c == '\\':
+			p.litEscape = true
+		case c == '"':
+			p.endLiteral(eof)
+		}
+		// 字符串的原始长度不能无限增长，包含两个引号
+		if max := p.p.limits.MaxStringLen; max > 0 && len(p.lit)-2 > max {
+			p.p.limitError("string length", int64(max), int(p.litPos.offset)+max+3)
+		}
+		return true
+	case 't', 'f', 'n':
+		if 'a' <= c && c <= 'z' {
+			p.lit = append(p.lit, c)
+			return true
+		}
+	default:
+		switch {
+		case '0' <= c && c <= '9', c == '-', c == '+', c == '.', c == 'e', c == 'E':
+			if max := p.p.limits.MaxNumberLen; max > 0 && len(p.lit) >= max {
+				p.p.limitError("number length", int64(max), int(p.litPos.offset)+max+1)
+			}
+			p.lit = append(p.lit, c)
+			return true
+		}
+	}
+	return false
+}
+
+// endLiteral 用 parser 解析已经完整的字面量，并把结果放到所在的容器中。
+// next 是字面量之后的字符，用于错误信息
+func (p *PushParser) endLiteral(next rune) {
+	lit := p.lit
+	p.lit = nil
+
+	sub := p.p.withData(lit)
+	var value any
+	func() {
+		defer p.relocate(lit, next)
+		value = sub.parseValue()
+		if !sub.end() {
+			sub.fail(p.expectedAfter())
+		}
+	}()
+	if sub.savedError != nil {
+		p.p.saveError(sub.savedError)
+	}
+
+	if p.litKey {
+		top := &p.stack[len(p.stack)-1]
+		top.key = value.(string)
+		top.keyPos = p.litPos.offset
+		p.state = pushColon
+		return
+	}
+	p.addValue(value)
+}
+
+// relocate 把字面量中的错误位置转换为在整个输入中的位置，字面量中不会出现换行
+func (p *PushParser) relocate(lit []byte, next rune) {
+	r := recover()
+	switch e := r.(type) {
+	case nil:
+		return
+	case *SyntaxError:
+		rel := int(e.Offset)
+		e.Offset += p.litPos.offset
+		e.Line = p.litPos.line
+		e.Column = p.litPos.column + utf8.RuneCount(lit[:rel])
+		if rel == len(lit) {
+			e.Char = next
+		}
+	case *json.LimitError:
+		e.Offset += p.litPos.offset
+	}
+	panic(r)
+}
+
+// addValue 把解析完成的值放到当前的容器中，没有容器时就是一个完整的顶层值
+func (p *PushParser) addValue(value any) {
+	if len(p.stack) == 0 {
+		p.values = append(p.values, value)
+		p.inValue = false
+		p.state = pushValue
+		return
+	}
+	top := &p.stack[len(p.stack)-1]
+	switch {
+	case !top.isObject:
+		top.array = append(top.array, value)
+	case p.p.ordered:
+		top.members = p.p.addMember(top.members, top.seen, top.key, value, int(top.keyPos))
+	default:
+		p.p.setKey(top.object, top.seen, top.key, value, int(top.keyPos))
+	}
+	p.state = pushCommaOrClose
+}
+
+// closeFrame 结束当前的 array 或 object
+func (p *PushParser) closeFrame() {
+	top := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	switch {
+	case !top.isObject:
+		p.addValue(top.array)
+	case p.p.ordered:
+		p.addValue(top.members)
+	default:
+		p.addValue(top.object)
+	}
+}
+
+// expected 返回当前状态下期望出现的内容，用于错误信息
+func (p *PushParser) expected() string {
+	switch p.state {
+	case pushValueOrClose, pushValue:
+		return "value"
+	case pushKeyOrClose, pushKey:
+		return "object key string"
+	case pushColon:
+		return "':' after object key"
+	}
+	return p.expectedAfter()
+}
+
+// expectedAfter 返回一个值结束之后期望出现的内容
+func (p *PushParser) expectedAfter() string {
+	switch {
+	case p.litKey:
+		return "':' after object key"
+	case len(p.stack) == 0:
+		return "end of value"
+	case p.stack[len(p.stack)-1].isObject:
+		return "',' or '}'"
+	default:
+		return "',' or ']'"
+	}
+}
+
+// fail 在当前字节处抛出 SyntaxError，rest 从当前字节开始
+func (p *PushParser) fail(rest []byte, expected string) {
+	r, _ := utf8.DecodeRune(rest)
+	p.failAt(p.pos, r, expected)
+}
+
+func (p *PushParser) failAt(pos position, char rune, expected string) {
+	panic(&SyntaxError{Offset: pos.offset, Line: pos.line, Column: pos.column, Char: char, Expected: expected})
+}
@@ -0,0 +1,181 @@
+package gjson
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+// pushAll 把 data 按 size 字节一段地交给 PushParser，返回所有解析出的值
+func pushAll(data string, size int, opts ...Option) ([]any, error) {
+	p := NewPushParser(opts...)
+	var values []any
+	collect := func() error {
+		for {
+			v, err := p.Next()
+			switch {
+			case err == nil:
+				values = append(values, v)
+			case errors.Is(err, ErrNeedMore), errors.Is(err, io.EOF):
+				return nil
+			default:
+				return err
+			}
+		}
+	}
+	for i := 0; i < len(data); i += size {
+		end := i + size
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := p.Push([]byte(data[i:end])); err != nil {
+			return values, err
+		}
+		if err := collect(); err != nil {
+			return values, err
+		}
+	}
+	if err := p.Close(); err != nil {
+		return values, err
+	}
+	return values, collect()
+}
+
+func TestPushParser(t *testing.T) {
+	docs := []string{
+		`true`,
+		`-12.5e+3`,
+		`"Hello \" world! 你好，世界！😀"`,
+		`[]`,
+		`{}`,
+		`[1, [2, [3, null]], {"a": false}]`,
+		`{"Image": {"Width": 800, "IDs": [116, 943], "Title": "View from 15th Floor"}}`,
+	}
+	for _, doc := range docs {
+		var expected any
+		if err := Unmarshal([]byte(doc), &expected); err != nil {
+			t.Fatalf("Unmarshal(%q): %v", doc, err)
+		}
+		for size := 1; size <= len(doc); size++ {
+			values, err := pushAll(doc, size)
+			if err != nil {
+				t.Fatalf("push %q in chunks of %d: %v", doc, size, err)
+			}
+			if !reflect.DeepEqual(values, []any{expected}) {
+				t.Fatalf("push %q in chunks of %d: %v, expected %v", doc, size, values, expected)
+			}
+		}
+	}
+}
+
+func TestPushParserStream(t *testing.T) {
+	p := NewPushParser()
+	if _, err := p.Next(); err != ErrNeedMore {
+		t.Fatalf("Next on empty parser: %v", err)
+	}
+	if err := p.Push([]byte(`{"a": [1, 2`)); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if _, err := p.Next(); err != ErrNeedMore {
+		t.Fatalf("Next on incomplete value: %v", err)
+	}
+	if err := p.Push([]byte("]}\n\"b\"\n12")); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	expected := []any{map[string]any{"a": []any{float64(1), float64(2)}}, "b"}
+	for _, e := range expected {
+		if v, err := p.Next(); err != nil || !reflect.DeepEqual(v, e) {
+			t.Fatalf("Next: %v, %v, expected %v", v, err, e)
+		}
+	}
+	// 数字需要遇到结束符或者输入结束才算完整
+	if _, err := p.Next(); err != ErrNeedMore {
+		t.Fatalf("Next on pending number: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if v, err := p.Next(); err != nil || v != float64(12) {
+		t.Fatalf("Next after Close: %v, %v", v, err)
+	}
+	if _, err := p.Next(); err != io.EOF {
+		t.Fatalf("Next after all values: %v", err)
+	}
+}
+
+func TestPushParserOptions(t *testing.T) {
+	values, err := pushAll(`{"b": 1, "a": 12345678901234567890}`, 3, OrderedObjects(), UseNumber(NumberString))
+	if err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	expected := Object{{"b", Number("1")}, {"a", Number("12345678901234567890")}}
+	if !reflect.DeepEqual(values, []any{expected}) {
+		t.Fatalf("push result %v, expected %v", values, expected)
+	}
+
+	_, err = pushAll(`{"a": 1, "a": 2}`, 1, DuplicateKeys(json.RejectDuplicateKeys))
+	if !reflect.DeepEqual(err, &json.DuplicateKeyError{Key: "a", Offset: 9}) {
+		t.Fatalf("duplicate key error %v", err)
+	}
+
+	// MaxBytes 限制的是单个顶层值
+	limits := json.Limits{MaxDepth: 2, MaxBytes: 6, MaxStringLen: 3}
+	if _, err := pushAll("[\"abc\"] [[1]]", 2, Limits(limits)); err != nil {
+		t.Fatalf("push within limits: %v", err)
+	}
+	limitTests := []struct {
+		data string
+		err  error
+	}{
+		{`[[[1]]]`, &json.LimitError{Limit: "depth", Max: 2, Offset: 3}},
+		{`1 [1, 2, 3]`, &json.LimitError{Limit: "bytes", Max: 6, Offset: 9}},
+		{`["abcd"]`, &json.LimitError{Limit: "string length", Max: 3, Offset: 7}},
+	}
+	for _, tt := range limitTests {
+		if _, err := pushAll(tt.data, 1, Limits(limits)); !reflect.DeepEqual(err, tt.err) {
+			t.Errorf("push %q error %v, expected %v", tt.data, err, tt.err)
+		}
+	}
+}
+
+func TestPushParserSyntaxError(t *testing.T) {
+	tests := []string{
+		`[1, 2,]`,
+		`{"a" 1}`,
+		"{\n  \"你好\": tru }",
+		`[01]`,
+		`["a\x"]`,
+		`{"a": 1,`,
+		`["abc`,
+		`[1.]`,
+	}
+	for _, data := range tests {
+		var v any
+		expected := Unmarshal([]byte(data), &v)
+		if expected == nil {
+			t.Fatalf("Unmarshal(%q) succeeded", data)
+		}
+		for _, size := range []int{1, 2, len(data)} {
+			_, err := pushAll(data, size)
+			if !reflect.DeepEqual(err, expected) {
+				t.Errorf("push %q in chunks of %d error %v, expected %v", data, size, err, expected)
+			}
+		}
+	}
+
+	// 出错之后 PushParser 不能继续使用
+	p := NewPushParser()
+	err := p.Push([]byte(`]`))
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if err2 := p.Push([]byte(`1`)); err2 != err {
+		t.Fatalf("Push after error: %v", err2)
+	}
+	if _, err2 := p.Next(); err2 != err {
+		t.Fatalf("Next after error: %v", err2)
+	}
+}
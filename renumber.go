@@ -0,0 +1,231 @@
+package gjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberStyle 是标题编号中每一级序列号的写法
+type NumberStyle int
+
+const (
+	// StyleDecimal 使用阿拉伯数字：1.2.3，这是默认的写法
+	StyleDecimal NumberStyle = iota
+	// StyleRoman 使用大写罗马数字：I.II.III
+	StyleRoman
+	// StyleLetters 使用大写字母，Z 之后是 AA：A.B.C
+	StyleLetters
+)
+
+type renumberConfig struct {
+	style      NumberStyle
+	startLevel int
+}
+
+// RenumberOption 设置 RenumberMarkdown 的编号方式
+type RenumberOption func(*renumberConfig)
+
+// WithNumberStyle 设置序列号的写法
+func WithNumberStyle(style NumberStyle) RenumberOption {
+	return func(c *renumberConfig) {
+		c.style = style
+	}
+}
+
+// WithStartLevel 设置开始编号的 level，更小 level 的标题不编号，也不参与编号的计算，
+// 例如 2 表示文档的 "#" 标题不编号，第一个 "##" 标题编号为 1
+func WithStartLevel(level int) RenumberOption {
+	return func(c *renumberConfig) {
+		c.startLevel = level
+	}
+}
+
+// RenumberMarkdown 按照 markdownParser 的规则为文档中的标题重新编号，例如 "## Title" 改写为 "## 1.2 Title"，
+// 只有一级的编号以 '.' 结尾，例如 "# 1. Title"。文档的其他部分保持不变。
+// 标题原有的编号会被替换而不是叠加，所以重复调用的结果相同。只有 RenumberMarkdown 可能生成的编号才会被当作原有的编号：
+// 段数和标题的层数相同，每一段是十进制数字（不能是 0，也不能以 0 开头）或者选中的写法的序列号，
+// 只有一段时以 '.' 结尾，后面是空白或者文本结尾，所以 "1.5.3 Bug fixes"、"U.S. History" 和 "2.0 Migration"
+// 这样的文本会被保留。小于开始编号 level 的标题只去掉原有的编号，层数按照开始 level 为 1 时的编号计算
+func RenumberMarkdown(doc string, opts ...RenumberOption) (string, error) {
+	config := renumberConfig{style: StyleDecimal, startLevel: 1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.startLevel < 1 || config.startLevel > 6 {
+		return "", fmt.Errorf("invalid start level %d", config.startLevel)
+	}
+	if config.style < StyleDecimal || config.style > StyleLetters {
+		return "", fmt.Errorf("invalid number style %d", config.style)
+	}
+	headings, err := parseHeadings(strings.NewReader(doc))
+	if err != nil {
+		return "", err
+	}
+	var selected []mdHeading
+	for _, h := range headings {
+		if h.level >= config.startLevel {
+			selected = append(selected, h)
+		}
+	}
+	// depths 是每个标题原有的编号应有的段数
+	depths := map[int]int{}
+	for i, title := range numberHeadings(headings) {
+		depths[headings[i].line] = strings.Count(title.level, ".") + 1
+	}
+	numbers := map[int]string{}
+	for i, title := range numberHeadings(selected) {
+		number := formatNumber(title.level, config.style)
+		if !strings.Contains(number, ".") {
+			number += "."
+		}
+		numbers[selected[i].line] = number
+		depths[selected[i].line] = strings.Count(title.level, ".") + 1
+	}
+
+	lines := strings.SplitAfter(doc, "\n")
+	for _, h := range headings {
+		lines[h.line-1] = renumberLine(lines[h.line-1], numbers[h.line], depths[h.line], config.style)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// renumberLine 把标题所在的行（setext 标题是文本的第一行）开头的编号替换为 number，number 为空时只去掉原有的编号。
+// depth 和 style 是原有的编号的段数和写法，见 stripNumber
+func renumberLine(line, number string, depth int, style NumberStyle) string {
+	content := strings.TrimRight(line, "\r\n")
+	eol := line[len(content):]
+	// 找到标题文本开始的位置，ATX 标题跳过 '#' 和之后的空白
+	_, rest := splitIndent(content)
+	pos := len(content) - len(rest)
+	if _, _, ok := parseATX(rest); ok {
+		pos += len(rest) - len(strings.TrimLeft(rest, "#"))
+		pos += len(content[pos:]) - len(strings.TrimLeft(content[pos:], " \t"))
+	}
+	text := stripNumber(content[pos:], depth, StyleDecimal, style)
+
+	prefix := content[:pos]
+	if number != "" {
+		if strings.HasSuffix(prefix, "#") {
+			// 只有 '#' 的空标题
+			prefix += " "
+		}
+		prefix += number
+		if text != "" {
+			prefix += " "
+		}
+	}
+	return prefix + text + eol
+}
+
+// stripNumber 去掉 text 开头 RenumberMarkdown 可能生成的编号和之后的空白，没有这样的编号时返回 text。
+// 编号有 depth 段，所有段都是 styles 中同一种写法的序列号；只有一段时以 '.' 结尾，多段时不以 '.' 结尾
+func stripNumber(text string, depth int, styles ...NumberStyle) string {
+	end := strings.IndexAny(text, " \t")
+	if end < 0 {
+		end = len(text)
+	}
+	number := text[:end]
+	if depth == 1 {
+		if !strings.HasSuffix(number, ".") {
+			return text
+		}
+		number = number[:len(number)-1]
+	}
+	serials := strings.Split(number, ".")
+	if len(serials) != depth {
+		return text
+	}
+	for _, style := range styles {
+		if validSerials(serials, style) {
+			return strings.TrimLeft(text[end:], " \t")
+		}
+	}
+	return text
+}
+
+// validSerials 检查每个序列号是否是 formatNumber 按照 style 生成的写法
+func validSerials(serials []string, style NumberStyle) bool {
+	for _, s := range serials {
+		if s == "" {
+			return false
+		}
+		switch style {
+		case StyleDecimal:
+			if s[0] == '0' || strings.Trim(s, "0123456789") != "" {
+				return false
+			}
+		case StyleRoman:
+			if n := fromRoman(s); n == 0 || toRoman(n) != s {
+				return false
+			}
+		case StyleLetters:
+			if strings.Trim(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// formatNumber 按照 style 改写 numberHeadings 生成的十进制编号，例如 "1.2.3" 改写为 "I.II.III"
+func formatNumber(level string, style NumberStyle) string {
+	if style == StyleDecimal {
+		return level
+	}
+	serials := strings.Split(level, ".")
+	for i, s := range serials {
+		n, _ := strconv.Atoi(s)
+		if style == StyleRoman {
+			serials[i] = toRoman(n)
+		} else {
+			serials[i] = toLetters(n)
+		}
+	}
+	return strings.Join(serials, ".")
+}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// toRoman 返回 n 的罗马数字，大于 3999 时使用更多的 M
+func toRoman(n int) string {
+	var b strings.Builder
+	for _, r := range romanNumerals {
+		for ; n >= r.value; n -= r.value {
+			b.WriteString(r.symbol)
+		}
+	}
+	return b.String()
+}
+
+// fromRoman 返回罗马数字 s 的值，s 包含其他字符时返回 0。
+// 不检查 s 是否是规范的写法，需要时和 toRoman 的结果比较
+func fromRoman(s string) int {
+	n := 0
+	for _, r := range romanNumerals {
+		for strings.HasPrefix(s, r.symbol) {
+			n += r.value
+			s = s[len(r.symbol):]
+		}
+	}
+	if s != "" {
+		return 0
+	}
+	return n
+}
+
+// toLetters 返回 n 对应的字母序列：1 -> A，26 -> Z，27 -> AA
+func toLetters(n int) string {
+	var b []byte
+	for ; n > 0; n = (n - 1) / 26 {
+		b = append([]byte{byte('A' + (n-1)%26)}, b...)
+	}
+	return string(b)
+}
@@ -0,0 +1,124 @@
+package gjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenumberMarkdown(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Guide",
+		"",
+		"## 3.1 Install ##",
+		"### 9.9.9 From source",
+		"```",
+		"## code",
+		"```",
+		"## 2024 Roadmap",
+		"Setext",
+		"------",
+		"##",
+		"#### I Am",
+		"",
+	}, "\r\n")
+	tests := []struct {
+		opts     []RenumberOption
+		expected []string
+	}{
+		{
+			nil,
+			[]string{"# 1. Guide", "## 1.1 Install ##", "### 1.1.1 From source", "## 1.2 2024 Roadmap", "1.3 Setext", "## 1.4", "#### 1.4.1 I Am"},
+		},
+		{
+			[]RenumberOption{WithStartLevel(2), WithNumberStyle(StyleRoman)},
+			// 原有的编号的段数和新的层数不同，不会被当作编号
+			[]string{"# Guide", "## I. 3.1 Install ##", "### I.I 9.9.9 From source", "## II. 2024 Roadmap", "III. Setext", "## IV.", "#### IV.I I Am"},
+		},
+		{
+			[]RenumberOption{WithStartLevel(3), WithNumberStyle(StyleLetters)},
+			[]string{"# Guide", "## Install ##", "### A. 9.9.9 From source", "## 2024 Roadmap", "Setext", "##", "#### A.A I Am"},
+		},
+	}
+	lines := []int{0, 2, 3, 7, 8, 10, 11}
+	for _, tt := range tests {
+		result, err := RenumberMarkdown(doc, tt.opts...)
+		if err != nil {
+			t.Fatalf("RenumberMarkdown error %v", err)
+		}
+		split := strings.Split(result, "\r\n")
+		var headings []string
+		for _, i := range lines {
+			headings = append(headings, split[i])
+		}
+		if strings.Join(headings, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("RenumberMarkdown = %q, expected %q", headings, tt.expected)
+		}
+		if split[5] != "## code" || split[9] != "------" || len(split) != 13 {
+			t.Errorf("RenumberMarkdown changed other lines: %q", result)
+		}
+		if again, err := RenumberMarkdown(result, tt.opts...); err != nil || again != result {
+			t.Errorf("RenumberMarkdown is not idempotent: %q, %v", again, err)
+		}
+	}
+
+	for _, opt := range []RenumberOption{WithStartLevel(0), WithStartLevel(7), WithNumberStyle(NumberStyle(9))} {
+		if _, err := RenumberMarkdown(doc, opt); err == nil {
+			t.Errorf("RenumberMarkdown: expected error")
+		}
+	}
+}
+
+func TestRenumberKeepsText(t *testing.T) {
+	tests := []struct {
+		doc      string
+		opts     []RenumberOption
+		expected string
+	}{
+		{
+			"## 1.5.3 Bug fixes\n## U.S. History\n## 2.0 Migration\n## 01. Zero\n",
+			nil,
+			"## 1. 1.5.3 Bug fixes\n## 2. U.S. History\n## 3. 2.0 Migration\n## 4. 01. Zero\n",
+		},
+		{
+			"# Q. Letters\n# IIII. Four\n# XI. Eleven\n",
+			nil,
+			"# 1. Q. Letters\n# 2. IIII. Four\n# 3. XI. Eleven\n",
+		},
+		{
+			"# Q. Letters\n# IIII. Four\n# XI. Eleven\n# 7. Seven\n",
+			[]RenumberOption{WithNumberStyle(StyleRoman)},
+			"# I. Q. Letters\n# II. IIII. Four\n# III. Eleven\n# IV. Seven\n",
+		},
+	}
+	for _, tt := range tests {
+		result, err := RenumberMarkdown(tt.doc, tt.opts...)
+		if err != nil || result != tt.expected {
+			t.Errorf("RenumberMarkdown(%q) = %q, %v, expected %q", tt.doc, result, err, tt.expected)
+		}
+	}
+}
+
+func TestNumberStyles(t *testing.T) {
+	tests := []struct {
+		n             int
+		roman, letter string
+	}{
+		{1, "I", "A"},
+		{4, "IV", "D"},
+		{9, "IX", "I"},
+		{14, "XIV", "N"},
+		{26, "XXVI", "Z"},
+		{27, "XXVII", "AA"},
+		{52, "LII", "AZ"},
+		{703, "DCCIII", "AAA"},
+		{1994, "MCMXCIV", "BXR"},
+	}
+	for _, tt := range tests {
+		if s := toRoman(tt.n); s != tt.roman {
+			t.Errorf("toRoman(%d) = %q, expected %q", tt.n, s, tt.roman)
+		}
+		if s := toLetters(tt.n); s != tt.letter {
+			t.Errorf("toLetters(%d) = %q, expected %q", tt.n, s, tt.letter)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package gjson
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// parseString 解析当前位置的字符串，按照 RFC 8259 处理所有的转义，返回解码后的内容
+func (t *parser) parseString() string {
+	// 跳过开头的引号，宽松语法下也可能是单引号
+	quote := t.data[t.index]
+	t.next()
+	start := t.index
+
+	// 大部分字符串没有转义，也都是合法的 UTF-8，可以直接截取
+	for !t.end() {
+		c := t.data[t.index]
+		if c == quote {
+			t.checkString(start)
+			s := string(t.data[start:t.index])
+			t.next()
+			return s
+		}
+		if c == '\\' || c < ' ' {
+			break
+		}
+		if c < utf8.RuneSelf {
+			t.next()
+			continue
+		}
+		r, size := utf8.DecodeRune(t.data[t.index:])
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+		t.index += size
+	}
+
+	b := make([]byte, 0, t.index-start+16)
+	b = append(b, t.data[start:t.index]...)
+	for {
+		t.checkString(start)
+		if t.end() {
+			t.fail("closing quote of string")
+		}
+		switch c := t.data[t.index]; {
+		case c == quote:
+			t.next()
+			return string(b)
+		case c == '\\':
+			b = t.parseEscape(b)
+		case c < ' ' && (!t.json5() || c == '\n' || c == '\r'):
+			// JSON5 只要求转义换行
+			t.fail("escaped control character in string")
+		case c < utf8.RuneSelf:
+			b = append(b, c)
+			t.next()
+		default:
+			r, size := utf8.DecodeRune(t.data[t.index:])
+			if r == utf8.RuneError && size == 1 {
+				// 非法的 UTF-8，默认替换为 U+FFFD
+				if t.rejectInvalidUTF8 {
+					t.fail("valid UTF-8 in string")
+				}
+			}
+			b = utf8.AppendRune(b, r)
+			t.index += size
+		}
+	}
+}
+
+// skipString 跳过当前位置的字符串，返回字符串内容在 t.data 中的范围。
+// 大部分字符串不需要分配内存；有转义或者非法 UTF-8 时交给 parseString 检查，
+// 这时 decode 为 true，表示内容需要用 parseString 解码后才能使用
+func (t *parser) skipString() (start, end int, decode bool) {
+	begin := t.index
+	quote := t.data[begin]
+	t.next()
+	start = t.index
+	for !t.end() {
+		c := t.data[t.index]
+		if c == quote {
+			t.checkString(start)
+			end = t.index
+			t.next()
+			return start, end, false
+		}
+		if c == '\\' || c < ' ' {
+			break
+		}
+		if c < utf8.RuneSelf {
+			t.next()
+			continue
+		}
+		r, size := utf8.DecodeRune(t.data[t.index:])
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+		t.index += size
+	}
+	t.index = begin
+	t.parseString()
+	return start, t.index - 1, true
+}
+
+// parseEscape 解析以 '\' 开头的转义序列，把结果追加到 b
+func (t *parser) parseEscape(b []byte) []byte {
+	// 跳过 '\'
+	t.next()
+	c := t.curChar()
+	switch c {
+	case '"', '\\', '/':
+		b = append(b, byte(c))
+	case '\'':
+		if !t.relaxed() {
+			t.fail("valid escape character")
+		}
+		b = append(b, '\'')
+	case 'b':
+		b = append(b, '\b')
+	case 'f':
+		b = append(b, '\f')
+	case 'n':
+		b = append(b, '\n')
+	case 'r':
+		b = append(b, '\r')
+	case 't':
+		b = append(b, '\t')
+	case 'u':
+		start := t.index - 1
+		r := t.parseHex4()
+		if utf16.IsSurrogate(r) {
+			// UTF-16 的代理对由两个 \uXXXX 组成
+			if t.curChar() == '\\' && t.index+1 < t.len && t.data[t.index+1] == 'u' {
+				next := t.index
+				t.next()
+				if dec := utf16.DecodeRune(r, t.parseHex4()); dec != utf8.RuneError {
+					return utf8.AppendRune(b, dec)
+				}
+				// 第二个 \uXXXX 不能和前面的组成代理对，交给下一轮单独处理
+				t.index = next
+			}
+			if t.rejectInvalidUTF8 {
+				t.failAt(start, "valid surrogate pair")
+			}
+			r = utf8.RuneError
+		}
+		return utf8.AppendRune(b, r)
+	default:
+		if t.json5() {
+			return t.parseEscape5(b)
+		}
+		t.fail("valid escape character")
+	}
+	t.next()
+	return b
+}
+
+// parseHex4 解析 \uXXXX 中的四个十六进制数字，调用时 t.index 指向 'u'
+func (t *parser) parseHex4() rune {
+	t.next()
+	var r rune
+	for i := 0; i < 4; i++ {
+		c := t.curChar()
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			t.fail("hexadecimal digit in \\u escape")
+		}
+		r = r*16 + c
+		t.next()
+	}
+	return r
+}
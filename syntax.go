@@ -0,0 +1,69 @@
+package gjson
+
+import (
+	"gjson/json"
+)
+
+// Syntax 设置 parser 接受的 JSON 方言，默认是严格的 json.StrictSyntax。
+// json.RelaxedSyntax 额外接受 // 和 /* */ 注释、array 和 object 最后一个元素之后的 ','，以及单引号字符串。
+// json.JSON5Syntax 在此基础上接受 JSON5 的其余语法：标识符形式的 key、十六进制数字、省略整数或小数部分的数字、
+// 开头的 '+'、Infinity、NaN，以及 JavaScript 的字符串转义和续行。JSON5 的数字改写为对应的 JSON 数字，
+// 例如 0x1F 解析为 31，.5 解析为 0.5，使用 NumberString 时得到的 Number 也是改写后的文本。
+// Syntax 对 ParseLazy 和 PushParser 没有作用，它们总是使用严格语法。
+func Syntax(syntax json.Syntax) Option {
+	return func(t *parser) {
+		t.syntax = syntax
+	}
+}
+
+// relaxed 判断是否使用了比 RFC 8259 宽松的语法
+func (t *parser) relaxed() bool {
+	return t.syntax != json.StrictSyntax
+}
+
+// valueChar 和 curChar 相同，只是宽松语法下把单引号也当作 '"'，
+// 这样按照值的第一个字符分派的地方不需要区分两种字符串
+func (t *parser) valueChar() rune {
+	c := t.curChar()
+	if c == '\'' && t.relaxed() {
+		return '"'
+	}
+	return c
+}
+
+// isComment 判断当前位置是否是注释的开头
+func (t *parser) isComment() bool {
+	return t.relaxed() && t.curChar() == '/' && t.index+1 < t.len &&
+		(t.data[t.index+1] == '/' || t.data[t.index+1] == '*')
+}
+
+// passComment 跳过当前位置的注释，宽松语法下由 passBlank 调用
+func (t *parser) passComment() {
+	if !t.isComment() {
+		t.failAt(t.index+1, "'/' or '*' after '/'")
+	}
+	if !t.skipComment() {
+		t.fail("'*/' at end of comment")
+	}
+}
+
+// skipComment 跳过当前位置的注释，返回注释是否完整。// 注释到换行或者输入结尾为止，
+// /* 注释没有结束时跳到输入结尾并返回 false
+func (t *parser) skipComment() bool {
+	t.next()
+	if t.curChar() == '/' {
+		for !t.end() && t.data[t.index] != '\n' {
+			t.next()
+		}
+		return true
+	}
+	t.next()
+	for !t.end() {
+		if t.data[t.index] == '*' && t.index+1 < t.len && t.data[t.index+1] == '/' {
+			t.index += 2
+			return true
+		}
+		t.next()
+	}
+	return false
+}
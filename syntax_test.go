@@ -0,0 +1,184 @@
+package gjson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestRelaxedSyntax(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected any
+		ok       bool
+	}{
+		{"// comment\n[1, /* two */ 2] // end", []any{float64(1), float64(2)}, true},
+		{"[1/**/,2]", []any{float64(1), float64(2)}, true},
+		{"/**/ 1 /* x */", float64(1), true},
+		{`[1, 2,]`, []any{float64(1), float64(2)}, true},
+		{`{"a": 1,}`, map[string]any{"a": float64(1)}, true},
+		{`{'a': 'b"c', "d": 'e\'f', 'g': "h\'i"}`, map[string]any{"a": `b"c`, "d": "e'f", "g": "h'i"}, true},
+		{`[1,,]`, nil, false},
+		{`[,]`, nil, false},
+		{`{,}`, nil, false},
+		{`{"a":,}`, nil, false},
+		{`[1] /* unterminated`, nil, false},
+		{`[1 / 2]`, nil, false},
+		{`'a"`, nil, false},
+		{`// only a comment`, nil, false},
+		{"[1,\v2]", nil, false},
+	}
+	for _, tt := range tests {
+		var v any
+		err := Unmarshal([]byte(tt.data), &v, Syntax(json.RelaxedSyntax))
+		if (err == nil) != tt.ok {
+			t.Errorf("Unmarshal(%q) error %v, expected ok=%v", tt.data, err, tt.ok)
+			continue
+		}
+		if tt.ok && !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("Unmarshal(%q) = %#v, expected %#v", tt.data, v, tt.expected)
+		}
+		// 严格语法是默认值，这些输入都不是合法的 RFC 8259 JSON
+		if err := Unmarshal([]byte(tt.data), &v); err == nil {
+			t.Errorf("Unmarshal(%q) without Syntax: expected error", tt.data)
+		}
+		if ok := json.ValidSyntax([]byte(tt.data), json.RelaxedSyntax); ok != tt.ok {
+			t.Errorf("json.ValidSyntax(%q) = %v, parser ok=%v", tt.data, ok, tt.ok)
+		}
+	}
+}
+
+func TestRelaxedSyntaxTyped(t *testing.T) {
+	var s struct {
+		Name string
+		Tags []string
+		N    int `json:",string"`
+	}
+	data := "{\n  'Name': 'x', // name\n  'Tags': ['a', 'b',],\n  'N': '7',\n}"
+	if err := Unmarshal([]byte(data), &s, Syntax(json.RelaxedSyntax)); err != nil {
+		t.Fatalf("Unmarshal error %v", err)
+	}
+	if s.Name != "x" || !reflect.DeepEqual(s.Tags, []string{"a", "b"}) || s.N != 7 {
+		t.Fatalf("Unmarshal = %+v", s)
+	}
+
+	v, err := Query([]byte(data), "Tags.#", Syntax(json.RelaxedSyntax))
+	if err != nil || v.Int() != 2 {
+		t.Fatalf("Query = %v, %v", v.Interface(), err)
+	}
+
+	err = Unmarshal([]byte(`{"a": "\'"}`), &s)
+	if e, ok := err.(*SyntaxError); !ok || e.Offset != 8 {
+		t.Fatalf("Unmarshal of \\' without Syntax: %v", err)
+	}
+}
+
+func TestRelaxedSyntaxTolerant(t *testing.T) {
+	var v any
+	data := `['a,]', x /* ], */, 'b',]`
+	err := Unmarshal([]byte(data), &v, Syntax(json.RelaxedSyntax), Tolerant())
+	errs, ok := err.(SyntaxErrors)
+	if !ok || len(errs) != 1 || errs[0].Offset != 8 {
+		t.Fatalf("Unmarshal error %v", err)
+	}
+	if expected := []any{"a,]", "b"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("Unmarshal = %#v, expected %#v", v, expected)
+	}
+}
+
+func TestJSON5Syntax(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected any
+		ok       bool
+	}{
+		{`{a: 1, $b_2: 'x', ключ: true,}`, map[string]any{"a": float64(1), "$b_2": "x", "ключ": true}, true},
+		{`{a /* c */ : 1}`, map[string]any{"a": float64(1)}, true},
+		{`[0x1F, -0xff, +7, .5, 5., -.5e1, 5.e1]`, []any{float64(31), float64(-255), float64(7), 0.5, float64(5), -5.0, float64(50)}, true},
+		{"\"a\\\nb\\\r\nc\\x41\\v\\0\\q\\ \"", "abcA\v\x00q ", true},
+		{`'é😀'`, "é😀", true},
+		{"'tab\there'", "tab\there", true},
+		{"\v[1,\v2]\f", []any{float64(1), float64(2)}, true},
+		{"{\fa:\v1\f}", map[string]any{"a": float64(1)}, true},
+		// 不支持非 ASCII 的空白
+		{"[1,\u00a02]", nil, false},
+		{`{2a: 1}`, nil, false},
+		{`{a-b: 1}`, nil, false},
+		{`[a]`, nil, false},
+		{`0x`, nil, false},
+		{`10x1`, nil, false},
+		{`.`, nil, false},
+		{`.e3`, nil, false},
+		{`01`, nil, false},
+		{`+`, nil, false},
+		{`Infinit`, nil, false},
+		{`"\01"`, nil, false},
+		{`"\1"`, nil, false},
+		{`"\x4"`, nil, false},
+		{"\"cr\rhere\"", nil, false},
+	}
+	for _, tt := range tests {
+		var v any
+		err := Unmarshal([]byte(tt.data), &v, Syntax(json.JSON5Syntax))
+		if (err == nil) != tt.ok {
+			t.Errorf("Unmarshal(%q) error %v, expected ok=%v", tt.data, err, tt.ok)
+			continue
+		}
+		if tt.ok && !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("Unmarshal(%q) = %#v, expected %#v", tt.data, v, tt.expected)
+		}
+		if ok := json.ValidSyntax([]byte(tt.data), json.JSON5Syntax); ok != tt.ok {
+			t.Errorf("json.ValidSyntax(%q) = %v, parser ok=%v", tt.data, ok, tt.ok)
+		}
+		var w any
+		if err := json.UnmarshalSyntax([]byte(tt.data), &w, json.JSON5Syntax); tt.ok && (err != nil || !reflect.DeepEqual(w, v)) {
+			t.Errorf("json.UnmarshalSyntax(%q) = %#v, %v, expected %#v", tt.data, w, err, v)
+		}
+	}
+}
+
+func TestJSON5Numbers(t *testing.T) {
+	data := []byte(`[0x10, +.5, Infinity, -Infinity, NaN]`)
+	v, err := ParseValue(data, Syntax(json.JSON5Syntax), UseNumber(NumberString))
+	if err != nil {
+		t.Fatalf("ParseValue error %v", err)
+	}
+	expected := []any{Number("16"), Number("0.5"), Number("Infinity"), Number("-Infinity"), Number("NaN")}
+	if !reflect.DeepEqual(v.Interface(), expected) {
+		t.Fatalf("ParseValue = %#v, expected %#v", v.Interface(), expected)
+	}
+
+	for _, mode := range []NumberMode{NumberFloat64, NumberInt64, NumberBig} {
+		v, err := ParseValue(data, Syntax(json.JSON5Syntax), UseNumber(mode))
+		if err != nil {
+			t.Fatalf("ParseValue(%v) error %v", mode, err)
+		}
+		if v.Index(0).Int() != 16 || v.Index(1).Float() != 0.5 ||
+			!math.IsInf(v.Index(2).Float(), 1) || !math.IsInf(v.Index(3).Float(), -1) || !math.IsNaN(v.Index(4).Float()) {
+			t.Errorf("ParseValue(%v) = %v", mode, v.Interface())
+		}
+	}
+
+	var s struct {
+		N   int
+		U   uint8
+		F   float64
+		Map map[int]string
+	}
+	if err := Unmarshal([]byte(`{N: 0x10, U: +0xff, F: -Infinity, Map: {'1': 'x'}}`), &s, Syntax(json.JSON5Syntax)); err != nil {
+		t.Fatalf("Unmarshal error %v", err)
+	}
+	if s.N != 16 || s.U != 255 || !math.IsInf(s.F, -1) || s.Map[1] != "x" {
+		t.Fatalf("Unmarshal = %+v", s)
+	}
+	if err := Unmarshal([]byte(`{N: NaN}`), &s, Syntax(json.JSON5Syntax)); err == nil {
+		t.Fatalf("Unmarshal NaN into int: expected error")
+	}
+
+	q, err := Query([]byte(`{list: [1, 2,], 'x': {y: 0x2A}}`), "x.y", Syntax(json.JSON5Syntax))
+	if err != nil || q.Int() != 42 {
+		t.Fatalf("Query = %v, %v", q.Interface(), err)
+	}
+}
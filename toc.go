@@ -0,0 +1,129 @@
+package gjson
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 目录在文档中的开始和结束标记，InsertTOC 替换它们之间的内容
+const (
+	tocBegin = "<!-- toc -->"
+	tocEnd   = "<!-- tocstop -->"
+)
+
+// slugger 生成和 GitHub 相同的标题锚点，同一个文档中的所有标题需要使用同一个 slugger，
+// 这样重复的标题才能得到 -1、-2 的后缀
+type slugger struct {
+	seen map[string]int
+}
+
+func newSlugger() *slugger {
+	return &slugger{seen: map[string]int{}}
+}
+
+// slug 返回标题文本对应的锚点：转为小写，去掉标点和符号，空白替换为 '-'，
+// 字母（包括中日韩文字）、数字、'-' 和 '_' 保留原样。
+// 文本中的行内 markdown 语法不做渲染，例如链接的 URL 也会出现在锚点中
+func (s *slugger) slug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteByte('-')
+		case r == '-', r == '_', unicode.IsLetter(r), unicode.IsNumber(r), unicode.IsMark(r):
+			b.WriteRune(r)
+		}
+	}
+	base := b.String()
+	slug := base
+	// 加上后缀之后可能和已有的锚点重复，例如 "a"、"a"、"a-1"，需要继续往下数
+	for n, ok := s.seen[base]; ok; _, ok = s.seen[slug] {
+		n++
+		s.seen[base] = n
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	s.seen[slug] = 0
+	return slug
+}
+
+// MarkdownTOC 为 markdown 文档生成目录，每个标题是一个指向它的锚点的列表项，子标题缩进两个空格。
+// 只有 level 在 [minLevel, maxLevel] 之间的标题出现在目录中，缩进按照 markdownParser 的规则反映这些标题的嵌套关系。
+// 目录以换行结束，没有符合条件的标题时返回空字符串
+func MarkdownTOC(r io.Reader, minLevel, maxLevel int) (string, error) {
+	if minLevel < 1 || maxLevel > 6 || minLevel > maxLevel {
+		return "", fmt.Errorf("invalid toc level range %d-%d", minLevel, maxLevel)
+	}
+	headings, err := parseHeadings(r)
+	if err != nil {
+		return "", err
+	}
+	// 锚点由所有的标题决定，不在目录中的标题也会影响重复锚点的后缀
+	slugs := newSlugger()
+	var anchors []string
+	var selected []mdHeading
+	for _, h := range headings {
+		anchor := slugs.slug(h.text)
+		if minLevel <= h.level && h.level <= maxLevel {
+			anchors = append(anchors, anchor)
+			selected = append(selected, h)
+		}
+	}
+
+	var b strings.Builder
+	for i, title := range numberHeadings(selected) {
+		depth := strings.Count(title.level, ".")
+		fmt.Fprintf(&b, "%s- [%s](#%s)\n", strings.Repeat("  ", depth), title.name, anchors[i])
+	}
+	return b.String(), nil
+}
+
+// InsertTOC 把 doc 的目录写到 tocBegin 和 tocEnd 两行之间，原来的内容被替换，其他部分保持不变。
+// 两个标记必须各自单独占一行，并且不在 fenced code block 中。重复调用的结果相同
+func InsertTOC(doc string, minLevel, maxLevel int) (string, error) {
+	lines := strings.SplitAfter(doc, "\n")
+	begin, end := -1, -1
+	fence := ""
+	for i, line := range lines {
+		indent, rest := splitIndent(strings.TrimRight(line, "\r\n"))
+		switch {
+		case fence != "":
+			if indent < 4 && isClosingFence(rest, fence) {
+				fence = ""
+			}
+		case indent >= 4:
+		case openingFence(rest) != "":
+			fence = openingFence(rest)
+		case strings.TrimSpace(rest) == tocBegin && begin < 0:
+			begin = i
+		case strings.TrimSpace(rest) == tocEnd && begin >= 0:
+			end = i
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if begin < 0 {
+		return "", errors.New("missing " + tocBegin + " marker")
+	}
+	if end < 0 {
+		return "", errors.New("missing " + tocEnd + " marker after " + tocBegin)
+	}
+
+	toc, err := MarkdownTOC(strings.NewReader(doc), minLevel, maxLevel)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, line := range lines[:begin+1] {
+		b.WriteString(line)
+	}
+	b.WriteString(toc)
+	for _, line := range lines[end:] {
+		b.WriteString(line)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,90 @@
+package gjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Hello World", "hello-world"},
+		{"Hello, World!", "hello-world-1"},
+		{"hello-world-1", "hello-world-1-1"},
+		{"Hello World", "hello-world-2"},
+		{"`Unmarshal` & *Marshal*", "unmarshal--marshal"},
+		{"snake_case  2.0", "snake_case--20"},
+		{"中文 标题（说明）", "中文-标题说明"},
+		{"Ünïcödé 😀", "ünïcödé-"},
+		{"", ""},
+		{"!!!", "-1"},
+	}
+	slugs := newSlugger()
+	for _, tt := range tests {
+		if slug := slugs.slug(tt.text); slug != tt.expected {
+			t.Errorf("slug(%q) = %q, expected %q", tt.text, slug, tt.expected)
+		}
+	}
+}
+
+func TestMarkdownTOC(t *testing.T) {
+	doc := strings.Join([]string{
+		"# Title",
+		"## Install",
+		"### From source",
+		"#### Details",
+		"## Usage",
+		"```",
+		"## not a heading",
+		"```",
+		"### Install",
+		"## 使用说明",
+	}, "\n")
+	tests := []struct {
+		min, max int
+		expected string
+	}{
+		{1, 6, "- [Title](#title)\n  - [Install](#install)\n    - [From source](#from-source)\n      - [Details](#details)\n  - [Usage](#usage)\n    - [Install](#install-1)\n  - [使用说明](#使用说明)\n"},
+		{2, 3, "- [Install](#install)\n  - [From source](#from-source)\n- [Usage](#usage)\n  - [Install](#install-1)\n- [使用说明](#使用说明)\n"},
+		{4, 6, "- [Details](#details)\n"},
+		{5, 6, ""},
+	}
+	for _, tt := range tests {
+		toc, err := MarkdownTOC(strings.NewReader(doc), tt.min, tt.max)
+		if err != nil {
+			t.Fatalf("MarkdownTOC(%d, %d) error %v", tt.min, tt.max, err)
+		}
+		if toc != tt.expected {
+			t.Errorf("MarkdownTOC(%d, %d) = %q, expected %q", tt.min, tt.max, toc, tt.expected)
+		}
+	}
+	for _, r := range [][2]int{{0, 3}, {3, 2}, {1, 7}} {
+		if _, err := MarkdownTOC(strings.NewReader(doc), r[0], r[1]); err == nil {
+			t.Errorf("MarkdownTOC(%d, %d): expected error", r[0], r[1])
+		}
+	}
+}
+
+func TestInsertTOC(t *testing.T) {
+	doc := "# Doc\n\n```\n<!-- toc -->\n```\n<!-- toc -->\nstale entry\n<!-- tocstop -->\n\n## A\n## B\n"
+	expected := "# Doc\n\n```\n<!-- toc -->\n```\n<!-- toc -->\n- [A](#a)\n- [B](#b)\n<!-- tocstop -->\n\n## A\n## B\n"
+	result, err := InsertTOC(doc, 2, 6)
+	if err != nil {
+		t.Fatalf("InsertTOC error %v", err)
+	}
+	if result != expected {
+		t.Fatalf("InsertTOC = %q, expected %q", result, expected)
+	}
+	// 再次插入的结果不变
+	if again, err := InsertTOC(result, 2, 6); err != nil || again != result {
+		t.Fatalf("InsertTOC again = %q, %v", again, err)
+	}
+
+	for _, doc := range []string{"# a\n", "<!-- toc -->\n# a\n", "<!-- tocstop -->\n<!-- toc -->\n"} {
+		if _, err := InsertTOC(doc, 1, 6); err == nil {
+			t.Errorf("InsertTOC(%q): expected error", doc)
+		}
+	}
+}
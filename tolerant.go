@@ -0,0 +1,119 @@
+package gjson
+
+import (
+	"fmt"
+)
+
+// Tolerant 打开容错模式：array 或 object 中的某个元素有语法错误时，跳过这个元素并记录错误，
+// 继续解析剩下的内容。Unmarshal 把尽力解析出的结果写入 v，并返回记录的所有错误 SyntaxErrors。
+// 容错模式只对解析到 any 和 Value 的结果生效，超出 Limits 的错误仍然会中断解析。
+func Tolerant() Option {
+	return func(t *parser) {
+		t.tolerant = true
+	}
+}
+
+// SyntaxErrors 是容错模式下记录的所有语法错误，按照在输入中出现的顺序排列
+type SyntaxErrors []*SyntaxError
+
+func (e SyntaxErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "gjson: no errors"
+	case 1:
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
+
+// catch 执行 f，容错模式下把 f 中的语法错误记录到 t.errors 并返回 false，
+// 其他模式下错误继续向上抛出
+func (t *parser) catch(f func()) (ok bool) {
+	if t.tolerant {
+		defer func() {
+			if r := recover(); r != nil {
+				e, isSyntax := r.(*SyntaxError)
+				if !isSyntax {
+					panic(r)
+				}
+				t.addError(e)
+				ok = false
+			}
+		}()
+	}
+	f()
+	return true
+}
+
+// addError 记录一个语法错误。一个未结束的值会让每一层容器都在同一个位置出错，只记录一次
+func (t *parser) addError(e *SyntaxError) {
+	if n := len(t.errors); n > 0 && t.errors[n-1].Offset == e.Offset {
+		return
+	}
+	t.errors = append(t.errors, e)
+}
+
+// element 解析容器中的一个元素以及之后的 ',' 或结束符 closing，返回容器是否已经结束。
+// 容错模式下元素出错时，从元素的开头跳到同一层的下一个 ',' 或结束符，继续解析后面的元素。
+func (t *parser) element(closing rune, f func() bool) (done bool) {
+	start := t.index
+	if t.catch(func() { done = f() }) {
+		return done
+	}
+	t.index = start
+	return t.resync(closing)
+}
+
+// resync 跳过出错的元素，直到同一层的 ','、结束符或者输入结尾，返回容器是否已经结束。
+// 遇到不匹配的结束符时认为容器已经结束，但不跳过它，留给外层的容器。
+func (t *parser) resync(closing rune) bool {
+	depth := 0
+	for !t.end() {
+		switch c := t.data[t.index]; c {
+		case '"', '\'':
+			if c == '"' || t.relaxed() {
+				t.skipBrokenString()
+				continue
+			}
+		case '/':
+			if t.isComment() {
+				t.skipComment()
+				continue
+			}
+		case '[', '{':
+			depth++
+		case ']', '}':
+			if depth == 0 {
+				if rune(t.data[t.index]) == closing {
+					t.next()
+				}
+				return true
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				t.next()
+				t.passBlank()
+				return false
+			}
+		}
+		t.next()
+	}
+	return true
+}
+
+// skipBrokenString 跳过一个可能不合法的字符串，只识别 '\' 和结尾的引号
+func (t *parser) skipBrokenString() {
+	quote := t.data[t.index]
+	t.next()
+	for !t.end() {
+		switch t.data[t.index] {
+		case '\\':
+			t.next()
+		case quote:
+			t.next()
+			return
+		}
+		t.next()
+	}
+}
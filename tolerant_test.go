@@ -0,0 +1,83 @@
+package gjson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTolerant(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected any
+		offsets  []int64
+	}{
+		{`[1, 2, 3]`, []any{float64(1), float64(2), float64(3)}, nil},
+		{`[1, x, 3]`, []any{float64(1), float64(3)}, []int64{4}},
+		{`[1, "a\q", tru, 4]`, []any{float64(1), float64(4)}, []int64{7, 14}},
+		{`[1 2, 3]`, []any{float64(1), float64(3)}, []int64{3}},
+		{`[1, 2,]`, []any{float64(1), float64(2)}, []int64{6}},
+		{`[[1, 2}, 3]`, []any{[]any{float64(1), float64(2)}, float64(3)}, []int64{6}},
+		{
+			`{"id": 7, "name": "x\yz", "tags": ["a", {"b" 1}], "ok": true}`,
+			map[string]any{"id": float64(7), "tags": []any{"a", map[string]any{}}, "ok": true},
+			[]int64{21, 45},
+		},
+		{`{"a": 1, 2: 3, "b": [}`, map[string]any{"a": float64(1), "b": []any(nil)}, []int64{9, 21}},
+		{`{"a": [1, {"b": 2`, map[string]any{"a": []any{float64(1), map[string]any{"b": float64(2)}}}, []int64{17}},
+		{`[1] x`, []any{float64(1)}, []int64{4}},
+		{`x`, nil, []int64{0}},
+	}
+	for _, tt := range tests {
+		var v any
+		err := Unmarshal([]byte(tt.data), &v, Tolerant())
+		if !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("Unmarshal(%q) = %#v, expected %#v", tt.data, v, tt.expected)
+		}
+		if tt.offsets == nil {
+			if err != nil {
+				t.Errorf("Unmarshal(%q) error %v", tt.data, err)
+			}
+			continue
+		}
+		var errs SyntaxErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("Unmarshal(%q) error %v, expected SyntaxErrors", tt.data, err)
+			continue
+		}
+		var offsets []int64
+		for _, e := range errs {
+			offsets = append(offsets, e.Offset)
+		}
+		if !reflect.DeepEqual(offsets, tt.offsets) {
+			t.Errorf("Unmarshal(%q) error offsets %v, expected %v: %v", tt.data, offsets, tt.offsets, err)
+		}
+	}
+}
+
+func TestTolerantValue(t *testing.T) {
+	v, err := ParseValue([]byte("{\n  \"a\": [1, ?],\n  \"b\": \"ok\"\n}"), Tolerant())
+	errs, ok := err.(SyntaxErrors)
+	if !ok || len(errs) != 1 || errs[0].Line != 2 || errs[0].Column != 12 {
+		t.Fatalf("ParseValue error %v", err)
+	}
+	if v.Get("b").String() != "ok" || v.Get("a").Len() != 1 {
+		t.Fatalf("ParseValue partial result %v", v.Interface())
+	}
+
+	// 没有打开容错模式时第一个错误就中断解析
+	if _, err := ParseValue([]byte(`[1, ?, ?]`)); err == nil {
+		t.Fatalf("expected error")
+	} else if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("expected *SyntaxError, got %T", err)
+	}
+}
+
+func TestTolerantTyped(t *testing.T) {
+	// 解析到具体类型时容错模式不生效，跳过的字段中的错误同样会返回
+	var s struct{ A int }
+	err := Unmarshal([]byte(`{"A": 1, "B": [1, ?]}`), &s, Tolerant())
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Fatalf("Unmarshal into struct error %v, expected *SyntaxError", err)
+	}
+}
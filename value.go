@@ -0,0 +1,301 @@
+package gjson
+
+import (
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"gjson/json"
+)
+
+// Kind 是 Value 中保存的 JSON 值的类型
+type Kind int
+
+const (
+	// KindMissing 表示值不存在，例如 Get 了一个没有的 key，和 JSON null 不同
+	KindMissing Kind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+var kindNames = []string{
+	KindMissing: "missing",
+	KindNull:    "null",
+	KindBool:    "bool",
+	KindNumber:  "number",
+	KindString:  "string",
+	KindArray:   "array",
+	KindObject:  "object",
+}
+
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Value 包装 parser 的解析结果，提供按类型访问的方法，不需要调用方自己做类型断言。
+// 零值表示不存在的值，所有访问方法在类型不符时都返回零值而不是 panic。
+type Value struct {
+	v      any
+	exists bool
+}
+
+// ParseValue 解析 data 并返回 Value，opts 和 Unmarshal 相同。
+// 容错模式下返回 SyntaxErrors 时，Value 是尽力解析出的结果。
+func ParseValue(data []byte, opts ...Option) (Value, error) {
+	var v any
+	err := Unmarshal(data, &v, opts...)
+	if _, partial := err.(SyntaxErrors); err != nil && !partial {
+		return Value{}, err
+	}
+	return ValueOf(v), err
+}
+
+// ValueOf 包装 Unmarshal 到 any 的结果
+func ValueOf(v any) Value {
+	return Value{v: v, exists: true}
+}
+
+// Kind 返回值的类型，数字不论使用哪种 NumberMode 都是 KindNumber
+func (v Value) Kind() Kind {
+	if !v.exists {
+		return KindMissing
+	}
+	switch v.v.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case float64, int64, Number, *big.Int, *BigFloat:
+		return KindNumber
+	case string:
+		return KindString
+	case []any:
+		return KindArray
+	case map[string]any, Object:
+		return KindObject
+	}
+	return KindMissing
+}
+
+// Exists 在值存在时返回 true，JSON null 也是存在的值
+func (v Value) Exists() bool {
+	return v.exists
+}
+
+// IsNull 只在值是 JSON null 时返回 true
+func (v Value) IsNull() bool {
+	return v.exists && v.v == nil
+}
+
+// Interface 返回包装的原始值，值不存在时返回 nil
+func (v Value) Interface() any {
+	return v.v
+}
+
+// Get 返回 object 中 key 对应的值，v 不是 object 或者没有这个 key 时返回不存在的值。
+// Object 中有重复的 key 时返回最后一个。
+func (v Value) Get(key string) Value {
+	switch o := v.v.(type) {
+	case map[string]any:
+		if value, ok := o[key]; ok {
+			return ValueOf(value)
+		}
+	case Object:
+		for i := len(o) - 1; i >= 0; i-- {
+			if o[i].Key == key {
+				return ValueOf(o[i].Value)
+			}
+		}
+	}
+	return Value{}
+}
+
+// Index 返回 array 中下标为 i 的元素，v 不是 array 或者下标越界时返回不存在的值
+func (v Value) Index(i int) Value {
+	if a, ok := v.v.([]any); ok && 0 <= i && i < len(a) {
+		return ValueOf(a[i])
+	}
+	return Value{}
+}
+
+// Len 返回 array 的元素个数或者 object 的成员个数，其他类型返回 0
+func (v Value) Len() int {
+	switch x := v.v.(type) {
+	case []any:
+		return len(x)
+	case map[string]any:
+		return len(x)
+	case Object:
+		return len(x)
+	}
+	return 0
+}
+
+// String 返回字符串的内容；数字、bool 返回它们的字面量，
+// array、object 返回 JSON 文本，null 和不存在的值返回空字符串
+func (v Value) String() string {
+	switch x := v.v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case Number:
+		return string(x)
+	case *big.Int:
+		return x.String()
+	case *BigFloat:
+		return x.Text('g', -1)
+	}
+	b, err := json.Marshal(v.v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Int 返回数字的整数部分，超出 int64 范围时返回最接近的 int64，不是数字时返回 0
+func (v Value) Int() int64 {
+	switch x := v.v.(type) {
+	case int64:
+		return x
+	case float64:
+		return floatToInt(x)
+	case Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return floatToInt(f)
+	case *big.Int:
+		if x.IsInt64() {
+			return x.Int64()
+		}
+		if x.Sign() < 0 {
+			return math.MinInt64
+		}
+		return math.MaxInt64
+	case *BigFloat:
+		i, _ := x.Int64()
+		return i
+	}
+	return 0
+}
+
+func floatToInt(f float64) int64 {
+	switch {
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(f)
+}
+
+// Float 返回数字的 float64 值，不是数字时返回 0
+func (v Value) Float() float64 {
+	switch x := v.v.(type) {
+	case float64:
+		return x
+	case int64:
+		return float64(x)
+	case Number:
+		f, _ := x.Float64()
+		return f
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(x).Float64()
+		return f
+	case *BigFloat:
+		f, _ := x.Float64()
+		return f
+	}
+	return 0
+}
+
+// Bool 返回 bool 值，不是 bool 时返回 false
+func (v Value) Bool() bool {
+	b, _ := v.v.(bool)
+	return b
+}
+
+// Array 返回 array 的所有元素，v 不是 array 时返回 nil
+func (v Value) Array() []Value {
+	a, ok := v.v.([]any)
+	if !ok {
+		return nil
+	}
+	values := make([]Value, len(a))
+	for i, item := range a {
+		values[i] = ValueOf(item)
+	}
+	return values
+}
+
+// Keys 返回 object 的所有 key，Object 按照原始顺序，map 按照字典序
+func (v Value) Keys() []string {
+	switch o := v.v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(o))
+		for key := range o {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	case Object:
+		return o.Keys()
+	}
+	return nil
+}
+
+// ForEach 依次对 array 的元素或者 object 的成员调用 fn，fn 返回 false 时停止。
+// array 的 key 是元素的下标，object 的顺序和 Keys 相同，其他类型不会调用 fn。
+func (v Value) ForEach(fn func(key string, value Value) bool) {
+	switch x := v.v.(type) {
+	case []any:
+		for i, item := range x {
+			if !fn(strconv.Itoa(i), ValueOf(item)) {
+				return
+			}
+		}
+	case map[string]any:
+		for _, key := range v.Keys() {
+			if !fn(key, ValueOf(x[key])) {
+				return
+			}
+		}
+	case Object:
+		for _, member := range x {
+			if !fn(member.Key, ValueOf(member.Value)) {
+				return
+			}
+		}
+	}
+}
+
+// MarshalJSON 输出包装的值，不存在的值输出为 null
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.v)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，这样 Value 也可以作为 struct 字段的类型
+func (v *Value) UnmarshalJSON(data []byte) error {
+	value, err := ParseValue(data)
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
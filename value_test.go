@@ -0,0 +1,132 @@
+package gjson
+
+import (
+	"reflect"
+	"testing"
+
+	"gjson/json"
+)
+
+func TestValue(t *testing.T) {
+	data := `{
+		"name": "gjson",
+		"stars": 1024,
+		"ratio": -1.5,
+		"big": 12345678901234567890,
+		"ok": true,
+		"none": null,
+		"tags": ["json", "parser"],
+		"owner": {"login": "alice", "id": 7}
+	}`
+	v, err := ParseValue([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseValue: %v", err)
+	}
+
+	if v.Kind() != KindObject || v.Len() != 8 {
+		t.Fatalf("Kind %v, Len %d", v.Kind(), v.Len())
+	}
+	if s := v.Get("name").String(); s != "gjson" {
+		t.Errorf("name %q", s)
+	}
+	if i := v.Get("stars").Int(); i != 1024 {
+		t.Errorf("stars %d", i)
+	}
+	if f := v.Get("ratio").Float(); f != -1.5 {
+		t.Errorf("ratio %v", f)
+	}
+	if i := v.Get("ratio").Int(); i != -1 {
+		t.Errorf("ratio as int %d", i)
+	}
+	if !v.Get("ok").Bool() {
+		t.Errorf("ok is false")
+	}
+	if s := v.Get("tags").Index(1).String(); s != "parser" {
+		t.Errorf("tags[1] %q", s)
+	}
+	if i := v.Get("owner").Get("id").Int(); i != 7 {
+		t.Errorf("owner.id %d", i)
+	}
+	if s := v.Get("tags").String(); s != `["json","parser"]` {
+		t.Errorf("tags as string %q", s)
+	}
+
+	// 不存在的值和 null 是不同的
+	none, missing := v.Get("none"), v.Get("nothing")
+	if none.Kind() != KindNull || !none.Exists() || !none.IsNull() {
+		t.Errorf("none: kind %v, exists %v", none.Kind(), none.Exists())
+	}
+	if missing.Kind() != KindMissing || missing.Exists() || missing.IsNull() {
+		t.Errorf("missing: kind %v, exists %v", missing.Kind(), missing.Exists())
+	}
+	// 访问不存在的值的成员不会 panic
+	if deep := v.Get("name").Get("x").Index(3); deep.Exists() || deep.String() != "" || deep.Int() != 0 {
+		t.Errorf("deep missing value %v", deep)
+	}
+	if v.Get("tags").Index(2).Exists() || v.Get("tags").Index(-1).Exists() {
+		t.Errorf("index out of range exists")
+	}
+
+	var keys []string
+	v.ForEach(func(key string, value Value) bool {
+		keys = append(keys, key)
+		return key != "none"
+	})
+	if !reflect.DeepEqual(keys, []string{"big", "name", "none"}) {
+		t.Errorf("ForEach keys %q", keys)
+	}
+	var tags []string
+	for _, tag := range v.Get("tags").Array() {
+		tags = append(tags, tag.String())
+	}
+	if !reflect.DeepEqual(tags, []string{"json", "parser"}) {
+		t.Errorf("Array %q", tags)
+	}
+}
+
+func TestValueNumberModes(t *testing.T) {
+	for _, mode := range []NumberMode{NumberFloat64, NumberString, NumberInt64, NumberBig} {
+		v, err := ParseValue([]byte(`[9007199254740993, 2.5]`), UseNumber(mode))
+		if err != nil {
+			t.Fatalf("ParseValue: %v", err)
+		}
+		if k := v.Index(0).Kind(); k != KindNumber {
+			t.Errorf("mode %d: kind %v", mode, k)
+		}
+		if f := v.Index(1).Float(); f != 2.5 {
+			t.Errorf("mode %d: Float %v", mode, f)
+		}
+		// float64 无法精确表示 9007199254740993
+		if mode != NumberFloat64 {
+			if i := v.Index(0).Int(); i != 9007199254740993 {
+				t.Errorf("mode %d: Int %d", mode, i)
+			}
+		}
+	}
+
+	v, err := ParseValue([]byte(`{"b": 1, "a": 2}`), OrderedObjects())
+	if err != nil {
+		t.Fatalf("ParseValue: %v", err)
+	}
+	if keys := v.Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Errorf("ordered keys %q", keys)
+	}
+}
+
+func TestValueField(t *testing.T) {
+	var s struct {
+		A Value
+		B Value
+		C Value
+	}
+	if err := Unmarshal([]byte(`{"A": {"x": [1]}, "B": null}`), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if s.A.Get("x").Index(0).Int() != 1 || !s.B.IsNull() || s.C.Exists() {
+		t.Fatalf("Unmarshal into Value fields: %+v", s)
+	}
+	b, err := json.Marshal(s.A)
+	if err != nil || string(b) != `{"x":[1]}` {
+		t.Fatalf("Marshal: %s, %v", b, err)
+	}
+}